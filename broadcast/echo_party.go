@@ -0,0 +1,225 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package broadcast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type (
+	// EchoParty wraps a keygen, signing or resharing tss.Party for transports that only offer authenticated
+	// point-to-point links. A broadcast message is handed to the wrapped party only once every other recipient
+	// has echoed the same hash of it, so a sender that shows different messages to different parties is blamed.
+	EchoParty struct {
+		tss.Party
+		params     *tss.Parameters
+		recipients Recipients
+		out        chan<- tss.Message
+
+		mtx   sync.Mutex
+		slots map[string]*slot
+	}
+
+	// slot holds the broadcast of one type from one sender along with the echoes received for it
+	slot struct {
+		msg       tss.ParsedMessage
+		hash      []byte
+		echoes    map[string][]byte // keyed by the echoing party's key
+		delivered bool
+	}
+)
+
+var _ tss.Party = (*EchoParty)(nil)
+
+// typeURLPrefix turns the type of a message into the type URL that it is classified by
+const typeURLPrefix = "type.googleapis.com/"
+
+// NewEchoParty wraps `party`. Echo messages are sent to `out`, which may be the channel the wrapped party sends to.
+// Messages that arrive from the transport must be passed to the EchoParty rather than to the wrapped party.
+func NewEchoParty(party tss.Party, params *tss.Parameters, recipients Recipients, out chan<- tss.Message) *EchoParty {
+	return &EchoParty{
+		Party:      party,
+		params:     params,
+		recipients: recipients,
+		out:        out,
+		slots:      make(map[string]*slot),
+	}
+}
+
+func (p *EchoParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *EchoParty) UpdateFromBytesWithRouting(wireBytes []byte, routing *tss.MessageRouting) (bool, *tss.Error) {
+	return tss.UpdateFromBytesWithRouting(p, wireBytes, routing)
+}
+
+func (p *EchoParty) Update(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if echo, ok := msg.Content().(*EchoMessage); ok {
+		return p.storeEcho(msg, echo)
+	}
+	if !msg.IsBroadcast() {
+		return p.Party.Update(msg)
+	}
+	return p.storeBroadcast(msg)
+}
+
+func (p *EchoParty) storeBroadcast(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	peers := p.echoPeers(msg.GetFrom().KeyInt(), msg.Type())
+
+	p.mtx.Lock()
+	s := p.slot(msg.GetFrom().GetKey(), msg.Type())
+	if s.msg != nil {
+		p.mtx.Unlock()
+		if bytes.Equal(s.hash, hashOf(msg)) {
+			return true, nil // retransmission
+		}
+		return false, p.WrapError(fmt.Errorf("%w: received two different %s broadcasts from %s",
+			tss.ErrEquivocation, msg.Type(), msg.GetFrom()), msg.GetFrom())
+	}
+	s.msg, s.hash = msg, hashOf(msg)
+	err := p.checkEchoes(s)
+	ready := err == nil && p.takeIfReady(s, peers)
+	p.mtx.Unlock()
+
+	if 0 < len(peers) {
+		echo := NewEchoMessage(peers, p.PartyID(), msg.GetFrom(), msg.Type(), s.hash)
+		if err := p.params.StampMessage(echo); err != nil {
+			return false, p.WrapError(err)
+		}
+		p.out <- echo
+	}
+	if err != nil {
+		return false, err
+	}
+	if ready {
+		return p.Party.Update(msg)
+	}
+	return true, nil
+}
+
+func (p *EchoParty) storeEcho(msg tss.ParsedMessage, echo *EchoMessage) (bool, *tss.Error) {
+	from := msg.GetFrom()
+	if from == nil || !from.ValidateBasic() || !msg.ValidateBasic() {
+		return false, p.WrapError(fmt.Errorf("received an invalid echo: %s", msg), from)
+	}
+	if !bytes.Equal(msg.WireMsg().GetSessionId(), p.params.SessionID()) {
+		return false, p.WrapError(errors.New("received an echo that belongs to another session"), from)
+	}
+	// an echo is checked like a direct message before it may blame anyone, as a forged one would blame its sender
+	if err := p.params.CheckVersion(msg); err != nil {
+		return false, p.WrapError(err, from)
+	}
+	if err := p.params.VerifyMessage(msg); err != nil {
+		return false, p.WrapError(err)
+	}
+	sender := new(big.Int).SetBytes(echo.GetSender())
+	if !containsKey(p.recipients(""), sender) {
+		return false, p.WrapError(fmt.Errorf("received an echo of a broadcast from %x, which is not a party", echo.GetSender()), from)
+	}
+	if class, err := tss.ClassifyTypeURL(typeURLPrefix + echo.GetType()); err != nil || !class.IsBroadcast() && class.Routing != tss.Routing_BROADCAST_OR_P2P {
+		return false, p.WrapError(fmt.Errorf("received an echo of %s, which is not a broadcast", echo.GetType()), from)
+	}
+	peers := p.echoPeers(sender, echo.GetType())
+	if !containsKey(peers, from.KeyInt()) {
+		return false, p.WrapError(fmt.Errorf("received an echo of %s from a party that does not receive it", echo.GetType()), from)
+	}
+
+	p.mtx.Lock()
+	s := p.slot(echo.GetSender(), echo.GetType())
+	if prev, ok := s.echoes[string(from.GetKey())]; ok {
+		p.mtx.Unlock()
+		if bytes.Equal(prev, echo.GetHash()) {
+			return true, nil // retransmission
+		}
+		return false, p.WrapError(fmt.Errorf("%w: received two different echoes of %s from %s",
+			tss.ErrEquivocation, echo.GetType(), from), from)
+	}
+	s.echoes[string(from.GetKey())] = echo.GetHash()
+	if s.msg == nil {
+		p.mtx.Unlock()
+		return true, nil
+	}
+	err := p.checkEchoes(s)
+	ready := err == nil && p.takeIfReady(s, peers)
+	p.mtx.Unlock()
+
+	if err != nil {
+		return false, err
+	}
+	if ready {
+		return p.Party.Update(s.msg)
+	}
+	return true, nil
+}
+
+// must be called with the mutex held
+func (p *EchoParty) slot(sender []byte, msgType string) *slot {
+	key := string(sender) + "/" + msgType
+	s, ok := p.slots[key]
+	if !ok {
+		s = &slot{echoes: make(map[string][]byte)}
+		p.slots[key] = s
+	}
+	return s
+}
+
+// checkEchoes blames the sender along with the echoing party when an echo does not match the received broadcast,
+// as it cannot be told which of the two is lying; must be called with the mutex held
+func (p *EchoParty) checkEchoes(s *slot) *tss.Error {
+	for _, Pj := range p.echoPeers(s.msg.GetFrom().KeyInt(), s.msg.Type()) {
+		if hash, ok := s.echoes[string(Pj.GetKey())]; ok && !bytes.Equal(hash, s.hash) {
+			return p.WrapError(fmt.Errorf("%w: %s echoed a different %s broadcast from %s",
+				tss.ErrEquivocation, Pj, s.msg.Type(), s.msg.GetFrom()), s.msg.GetFrom(), Pj)
+		}
+	}
+	return nil
+}
+
+// takeIfReady marks the broadcast as delivered once every peer has echoed it; must be called with the mutex held
+func (p *EchoParty) takeIfReady(s *slot, peers []*tss.PartyID) bool {
+	if s.delivered || len(s.echoes) < len(peers) {
+		return false
+	}
+	s.delivered = true
+	return true
+}
+
+// echoPeers are the recipients of a broadcast other than this party and the sender
+func (p *EchoParty) echoPeers(sender *big.Int, msgType string) []*tss.PartyID {
+	self := p.PartyID().KeyInt()
+	recipients := p.recipients(msgType)
+	peers := make([]*tss.PartyID, 0, len(recipients))
+	for _, Pj := range recipients {
+		if key := Pj.KeyInt(); key.Cmp(self) != 0 && key.Cmp(sender) != 0 {
+			peers = append(peers, Pj)
+		}
+	}
+	return peers
+}
+
+func containsKey(parties []*tss.PartyID, key *big.Int) bool {
+	for _, Pj := range parties {
+		if Pj.KeyInt().Cmp(key) == 0 {
+			return true
+		}
+	}
+	return false
+}
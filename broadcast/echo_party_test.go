@@ -0,0 +1,188 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package broadcast
+
+import (
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+// newEchoKeygenParties wraps one eddsa keygen party per id in an EchoParty; all of them send to `outCh`
+func newEchoKeygenParties(pIDs tss.SortedPartyIDs, outCh chan tss.Message, endCh chan *keygen.LocalPartySaveData) []*EchoParty {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*EchoParty, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), test.TestThreshold)
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, NewEchoParty(P, params, AllParties(params), outCh))
+	}
+	return parties
+}
+
+// deliver sends msg point-to-point to each of its recipients, including every other party for a broadcast
+func deliver(parties []*EchoParty, msg tss.Message, errCh chan *tss.Error) {
+	dest := msg.GetTo()
+	if dest == nil {
+		for _, P := range parties {
+			if P.PartyID().Index != msg.GetFrom().Index {
+				go test.SharedPartyUpdater(P, msg, errCh)
+			}
+		}
+		return
+	}
+	for _, to := range dest {
+		go test.SharedPartyUpdater(parties[to.Index], msg, errCh)
+	}
+}
+
+func TestE2EKeygenOverEchoBroadcast(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := newEchoKeygenParties(pIDs, outCh, endCh)
+	for _, P := range parties {
+		go func(P *EchoParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var echoes int
+	saves := make([]*keygen.LocalPartySaveData, 0, len(pIDs))
+	for len(saves) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			if _, ok := msg.(tss.ParsedMessage).Content().(*EchoMessage); ok {
+				echoes++
+			}
+			deliver(parties, msg, errCh)
+		case save := <-endCh:
+			saves = append(saves, save)
+		}
+	}
+	for _, save := range saves[1:] {
+		assert.True(t, saves[0].EDDSAPub.Equals(save.EDDSAPub), "all parties should agree on the public key")
+	}
+	// two broadcast rounds, each echoed by every recipient to the other recipients
+	assert.Equal(t, 2*len(pIDs)*(len(pIDs)-1), echoes)
+}
+
+func TestEquivocatingBroadcastIsBlamed(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(3)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := newEchoKeygenParties(pIDs, outCh, endCh)
+	for _, P := range parties[1:] {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// P[1] shows one round 1 commitment to P[2] and P[3] another
+	go test.SharedPartyUpdater(parties[1], keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)), errCh)
+	go test.SharedPartyUpdater(parties[2], keygen.NewKGRound1Message(pIDs[0], big.NewInt(2)), errCh)
+
+	for {
+		select {
+		case err := <-errCh:
+			assert.True(t, errors.Is(err, tss.ErrEquivocation), "error should be equivocation: %v", err)
+			assert.Contains(t, err.Culprits(), pIDs[0])
+			return
+		case msg := <-outCh:
+			if msg.GetFrom().Index != 0 {
+				deliver(parties, msg, errCh)
+			}
+		case <-endCh:
+			t.Fatal("keygen should not finish")
+		}
+	}
+}
+
+// newSignedEchoKeygenParties is newEchoKeygenParties for parties that sign their messages with identity keys
+func newSignedEchoKeygenParties(t *testing.T, pIDs tss.SortedPartyIDs, outCh chan tss.Message) ([]*EchoParty, *test.Session) {
+	session := test.NewSession(tss.Edwards(), pIDs, test.TestThreshold, nil)
+	if err := session.SetIdentityKeys(); err != nil {
+		t.Fatal(err)
+	}
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	parties := make([]*EchoParty, 0, len(pIDs))
+	for _, params := range session.Params {
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, NewEchoParty(P, params, AllParties(params), outCh))
+	}
+	return parties, session
+}
+
+func TestForgedEchoIsRejected(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(3)
+	parties, session := newSignedEchoKeygenParties(t, pIDs, make(chan tss.Message, 100))
+
+	// P[3] echoes its own round 1 broadcast to P[1] as if it were P[2], with a hash that does not match the broadcast
+	typ := string(proto.MessageName(&keygen.KGRound1Message{}))
+	forged := NewEchoMessage([]*tss.PartyID{pIDs[0]}, pIDs[1], pIDs[2], typ, make([]byte, sha512.Size256))
+	assert.NoError(t, session.Params[2].StampMessage(forged))
+	ok, err := parties[0].Update(forged)
+	assert.False(t, ok)
+	if assert.NotNil(t, err) {
+		assert.ErrorIs(t, err, tss.ErrInvalidSignature)
+		assert.NotContains(t, err.Culprits(), pIDs[1], "P[2] must not be blamed for an echo that it did not send")
+	}
+	assert.Empty(t, parties[0].slots)
+}
+
+func TestEchoOfUnknownSenderIsRejected(t *testing.T) {
+	setUp("info")
+
+	pIDs := tss.GenerateTestPartyIDs(3)
+	parties, session := newSignedEchoKeygenParties(t, pIDs, make(chan tss.Message, 100))
+	stranger := tss.NewPartyID("stranger", "stranger", big.NewInt(12345))
+	keygenType := string(proto.MessageName(&keygen.KGRound1Message{}))
+	p2pType := string(proto.MessageName(&keygen.KGRound2Message1{}))
+
+	for _, echo := range []tss.ParsedMessage{
+		// of a broadcast from a party that is not in the session
+		NewEchoMessage([]*tss.PartyID{pIDs[0]}, pIDs[1], stranger, keygenType, make([]byte, sha512.Size256)),
+		// of a type that is not a message of the library, or not a broadcast
+		NewEchoMessage([]*tss.PartyID{pIDs[0]}, pIDs[1], pIDs[2], "binance.tsslib.Unknown", make([]byte, sha512.Size256)),
+		NewEchoMessage([]*tss.PartyID{pIDs[0]}, pIDs[1], pIDs[2], p2pType, make([]byte, sha512.Size256)),
+	} {
+		assert.NoError(t, session.Params[1].StampMessage(echo))
+		ok, err := parties[0].Update(echo)
+		assert.False(t, ok)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, []*tss.PartyID{pIDs[1]}, err.Culprits())
+		}
+	}
+	assert.Empty(t, parties[0].slots, "a rejected echo must not take a slot")
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package broadcast
+
+import (
+	"crypto/sha512"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into broadcast.pb.go
+// The following messages are registered on the Protocol Buffers "wire"
+
+var (
+	// Ensure that broadcast messages implement ValidateBasic
+	_ = []tss.MessageContent{
+		(*EchoMessage)(nil),
+	}
+)
+
+// ----- //
+
+func NewEchoMessage(
+	to []*tss.PartyID,
+	from *tss.PartyID,
+	sender *tss.PartyID,
+	msgType string,
+	hash []byte,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          to,
+		IsBroadcast: false,
+	}
+	content := &EchoMessage{
+		Sender: sender.GetKey(),
+		Type:   msgType,
+		Hash:   hash,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *EchoMessage) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetSender()) &&
+		m.GetType() != "" &&
+		len(m.GetHash()) == sha512.Size256
+}
+
+// ----- //
+
+// hashOf is the digest of a broadcast message that its recipients echo to each other
+func hashOf(msg tss.ParsedMessage) []byte {
+	content := msg.WireMsg().GetMessage()
+	return common.SHA512_256([]byte(content.GetTypeUrl()), content.GetValue())
+}
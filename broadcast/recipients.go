@@ -0,0 +1,52 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package broadcast
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	ecdsaResharing "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	eddsaResharing "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Recipients returns the parties that a broadcast message of type `msgType` is sent to. Given the empty type, it returns
+// every party of the session, which are the only ones that may send a broadcast.
+type Recipients func(msgType string) []*tss.PartyID
+
+var (
+	// resharing broadcasts that are not sent to the new committee only
+	toOldCommittee = map[string]bool{
+		string(proto.MessageName(&ecdsaResharing.DGRound2Message2{})): true,
+		string(proto.MessageName(&eddsaResharing.DGRound2Message{})):  true,
+	}
+	toOldAndNewCommittees = map[string]bool{
+		string(proto.MessageName(&ecdsaResharing.DGRound4Message2{})): true,
+		string(proto.MessageName(&eddsaResharing.DGRound4Message{})):  true,
+	}
+)
+
+// AllParties is used for keygen and signing, where every broadcast is sent to all of the parties
+func AllParties(params *tss.Parameters) Recipients {
+	return func(string) []*tss.PartyID {
+		return params.Parties().IDs()
+	}
+}
+
+// ReSharingCommittees is used for resharing, where each type of broadcast goes to the old committee, the new one or both
+func ReSharingCommittees(params *tss.ReSharingParameters) Recipients {
+	return func(msgType string) []*tss.PartyID {
+		switch {
+		case msgType == "", toOldAndNewCommittees[msgType]:
+			return params.OldAndNewParties()
+		case toOldCommittee[msgType]:
+			return params.OldParties().IDs()
+		default:
+			return params.NewParties().IDs()
+		}
+	}
+}
@@ -8,6 +8,7 @@ package common
 
 import (
 	"math/big"
+	"math/bits"
 )
 
 func BigIntsToBytes(bigInts []*big.Int) [][]byte {
@@ -51,6 +52,42 @@ func NonEmptyMultiBytes(bzs [][]byte, expectLen ...int) bool {
 	return true
 }
 
+// BytesBitLen returns the bit length of the big-endian unsigned integer in bz, ignoring any leading zero bytes
+func BytesBitLen(bz []byte) int {
+	for i, b := range bz {
+		if b != 0 {
+			return (len(bz)-i-1)*8 + bits.Len8(b)
+		}
+	}
+	return 0
+}
+
+// Returns true when the byte slice holds an integer of at most maxBits bits, without more padding than that integer needs
+func BytesMaxBits(bz []byte, maxBits int) bool {
+	return len(bz) <= (maxBits+7)/8 && BytesBitLen(bz) <= maxBits
+}
+
+// Returns true when the byte slice is non-empty and holds an integer of at most maxBits bits
+func NonEmptyBytesMaxBits(bz []byte, maxBits int) bool {
+	return NonEmptyBytes(bz) && BytesMaxBits(bz, maxBits)
+}
+
+// Returns true when all of the slices in the multi-dimensional byte slice are non-empty and hold integers of at most maxBits bits
+func NonEmptyMultiBytesMaxBits(bzs [][]byte, maxBits int, expectLen ...int) bool {
+	return NonEmptyMultiBytes(bzs, expectLen...) && MultiBytesMaxBits(bzs, maxBits)
+}
+
+// Returns true when none of the slices in the multi-dimensional byte slice holds an integer of more than maxBits bits.
+// Unlike NonEmptyMultiBytesMaxBits it accepts empty slices, for the optional parts of a message.
+func MultiBytesMaxBits(bzs [][]byte, maxBits int) bool {
+	for _, bz := range bzs {
+		if !BytesMaxBits(bz, maxBits) {
+			return false
+		}
+	}
+	return true
+}
+
 // PadToLengthBytesInPlace pad {0, ...} to the front of src if len(src) < length
 // output length is equal to the parameter length
 func PadToLengthBytesInPlace(src []byte, length int) []byte {
@@ -0,0 +1,42 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestBytesBitLen(t *testing.T) {
+	for _, bits := range []int{1, 7, 8, 9, 255, 256, 2048} {
+		n := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		assert.Equal(t, bits, common.BytesBitLen(n.Bytes()))
+		// leading zero bytes do not count
+		assert.Equal(t, bits, common.BytesBitLen(append([]byte{0, 0}, n.Bytes()...)))
+	}
+	assert.Equal(t, 0, common.BytesBitLen(nil))
+	assert.Equal(t, 0, common.BytesBitLen([]byte{0}))
+}
+
+func TestBytesMaxBits(t *testing.T) {
+	n := new(big.Int).Lsh(big.NewInt(1), 255) // 256 bits
+	assert.True(t, common.NonEmptyBytesMaxBits(n.Bytes(), 256))
+	assert.False(t, common.NonEmptyBytesMaxBits(n.Bytes(), 255))
+	assert.False(t, common.NonEmptyBytesMaxBits(nil, 256))
+	// padding beyond the size of the largest allowed integer is rejected
+	assert.False(t, common.NonEmptyBytesMaxBits(append([]byte{0}, n.Bytes()...), 256))
+
+	assert.True(t, common.NonEmptyMultiBytesMaxBits([][]byte{n.Bytes(), {1}}, 256, 2))
+	assert.False(t, common.NonEmptyMultiBytesMaxBits([][]byte{n.Bytes(), {1}}, 255, 2))
+	assert.False(t, common.NonEmptyMultiBytesMaxBits([][]byte{n.Bytes(), {}}, 256, 2))
+	assert.True(t, common.MultiBytesMaxBits([][]byte{n.Bytes(), {}}, 256))
+	assert.False(t, common.MultiBytesMaxBits([][]byte{{}, n.Bytes()}, 8))
+}
@@ -11,8 +11,11 @@ import (
 	"crypto/sha512"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"hash"
 	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
@@ -116,7 +119,10 @@ func NewExtendedKeyFromString(key string, curve elliptic.Curve) (*ExtendedKey, e
 			Y:     pk.Y(),
 		}
 	} else {
-		px, py := elliptic.Unmarshal(curve, keyData)
+		px, py := elliptic.UnmarshalCompressed(curve, keyData)
+		if px == nil {
+			return nil, errors.New("invalid extended key")
+		}
 		pubKey = ecdsa.PublicKey{
 			Curve: curve,
 			X:     px,
@@ -180,6 +186,27 @@ func serializeCompressed(publicKeyX *big.Int, publicKeyY *big.Int) []byte {
 	return paddedAppend(b, 32, publicKeyX.Bytes())
 }
 
+// ParsePath parses a derivation path such as "m/44/60/0/0/5" into its indices.
+// Hardened indices, marked with ' or h, are refused as they cannot be derived from a public key.
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if 0 < len(parts) && parts[0] == "m" {
+		parts = parts[1:]
+	}
+	indices := make([]uint32, 0, len(parts))
+	for _, part := range parts {
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H") {
+			return nil, fmt.Errorf("the path %q has a hardened index", path)
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || HardenedKeyStart <= index {
+			return nil, fmt.Errorf("the path %q has an invalid index %q", path, part)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
 func DeriveChildKeyFromHierarchy(indicesHierarchy []uint32, pk *ExtendedKey, mod *big.Int, curve elliptic.Curve) (*big.Int, *ExtendedKey, error) {
 	var k = pk
 	var err error
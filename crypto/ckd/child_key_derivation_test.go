@@ -7,8 +7,12 @@
 package ckd_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"testing"
 
+	"github.com/bnb-chain/tss-lib/v2/crypto"
 	. "github.com/bnb-chain/tss-lib/v2/crypto/ckd"
 	"github.com/btcsuite/btcd/btcec/v2"
 )
@@ -130,3 +134,64 @@ tests:
 		}
 	}
 }
+
+func TestPublicDerivationP256(t *testing.T) {
+	curve := elliptic.P256()
+	sk, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master := &ExtendedKey{
+		PublicKey: sk.PublicKey,
+		ChainCode: make([]byte, 32),
+		ParentFP:  []byte{0x00, 0x00, 0x00, 0x00},
+		Version:   []byte{0x04, 0x88, 0xb2, 0x1e},
+	}
+
+	// the serialized key must decode to the same point on P-256
+	parsed, err := NewExtendedKeyFromString(master.String(), curve)
+	if err != nil {
+		t.Fatalf("NewExtendedKeyFromString: unexpected error: %v", err)
+	}
+	if parsed.X.Cmp(master.X) != 0 || parsed.Y.Cmp(master.Y) != 0 {
+		t.Fatal("mismatched public key after decoding the serialized master key")
+	}
+
+	path := []uint32{0, 1, 2}
+	il, child, err := DeriveChildKeyFromHierarchy(path, parsed, curve.Params().N, curve)
+	if err != nil {
+		t.Fatalf("DeriveChildKeyFromHierarchy: unexpected error: %v", err)
+	}
+	masterPt, err := crypto.NewECPoint(curve, master.X, master.Y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := masterPt.Add(crypto.ScalarBaseMult(curve, il))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.X.Cmp(want.X()) != 0 || child.Y.Cmp(want.Y()) != 0 {
+		t.Fatal("child key should be the master key plus il*G")
+	}
+	if _, err = NewExtendedKeyFromString(child.String(), curve); err != nil {
+		t.Fatalf("NewExtendedKeyFromString: unexpected error decoding the child key: %v", err)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	indices, err := ParsePath("m/44/60/0/0/5")
+	if err != nil {
+		t.Fatalf("ParsePath: unexpected error: %v", err)
+	}
+	if len(indices) != 5 || indices[0] != 44 || indices[1] != 60 || indices[4] != 5 {
+		t.Fatalf("ParsePath: unexpected indices %v", indices)
+	}
+	if indices, err = ParsePath("m"); err != nil || len(indices) != 0 {
+		t.Fatalf("ParsePath: the master path gives no indices, got %v, %v", indices, err)
+	}
+	for _, path := range []string{"m/44'/60", "m/44h", "m/x", "m/2147483648", "m//1"} {
+		if _, err := ParsePath(path); err == nil {
+			t.Fatalf("ParsePath: expected an error for %q", path)
+		}
+	}
+}
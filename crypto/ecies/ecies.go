@@ -0,0 +1,189 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ecies
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// MaxOverhead is the most that Encrypt adds to the length of a plaintext: an uncompressed P-521 ephemeral key and the GCM tag
+const MaxOverhead = 1 + 2*66 + 16
+
+var kdfInfo = []byte("tss-lib ecies")
+
+// Encrypt encrypts `plaintext` to `pub`, an ed25519.PublicKey or *ecdsa.PublicKey, binding it to `aad`.
+// A fresh ephemeral key is agreed with `pub` for every call; an Ed25519 key is used through its X25519 form.
+func Encrypt(rand io.Reader, pub crypto.PublicKey, plaintext, aad []byte) ([]byte, error) {
+	var ephPub, recipient, shared []byte
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		u, err := x25519PublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		scalar := make([]byte, curve25519.ScalarSize)
+		if _, err := io.ReadFull(rand, scalar); err != nil {
+			return nil, err
+		}
+		if ephPub, err = curve25519.X25519(scalar, curve25519.Basepoint); err != nil {
+			return nil, err
+		}
+		if shared, err = curve25519.X25519(scalar, u); err != nil {
+			return nil, err
+		}
+		recipient = u
+	case *ecdsa.PublicKey:
+		if pub == nil || pub.X == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("ecies: invalid public key")
+		}
+		eph, err := ecdsa.GenerateKey(pub.Curve, rand)
+		if err != nil {
+			return nil, err
+		}
+		ephPub = marshalPoint(pub.Curve, eph.X, eph.Y)
+		recipient = marshalPoint(pub.Curve, pub.X, pub.Y)
+		sx, _ := pub.Curve.ScalarMult(pub.X, pub.Y, eph.D.Bytes())
+		shared = sx.FillBytes(make([]byte, byteLen(pub.Curve)))
+	default:
+		return nil, fmt.Errorf("ecies: unsupported public key type %T", pub)
+	}
+	aead, err := newAEAD(shared, ephPub, recipient)
+	if err != nil {
+		return nil, err
+	}
+	// the key is used only once, so a fixed nonce is safe
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ephPub, nonce, plaintext, aad), nil
+}
+
+// Decrypt opens a ciphertext made by Encrypt with the private key, an ed25519.PrivateKey or *ecdsa.PrivateKey,
+// that matches the public key it was encrypted to
+func Decrypt(priv crypto.PrivateKey, ciphertext, aad []byte) ([]byte, error) {
+	var ephPub, recipient, shared []byte
+	switch priv := priv.(type) {
+	case ed25519.PrivateKey:
+		if len(priv) != ed25519.PrivateKeySize || len(ciphertext) < curve25519.PointSize {
+			return nil, errors.New("ecies: invalid key or ciphertext")
+		}
+		h := sha512.Sum512(priv.Seed())
+		scalar := h[:curve25519.ScalarSize]
+		ephPub = ciphertext[:curve25519.PointSize]
+		var err error
+		if shared, err = curve25519.X25519(scalar, ephPub); err != nil {
+			return nil, err
+		}
+		if recipient, err = curve25519.X25519(scalar, curve25519.Basepoint); err != nil {
+			return nil, err
+		}
+	case *ecdsa.PrivateKey:
+		if priv == nil || priv.D == nil {
+			return nil, errors.New("ecies: invalid private key")
+		}
+		ec := priv.Curve
+		if ptLen := 1 + 2*byteLen(ec); ptLen <= len(ciphertext) {
+			ephPub = ciphertext[:ptLen]
+		} else {
+			return nil, errors.New("ecies: ciphertext too short")
+		}
+		ex, ey, ok := unmarshalPoint(ec, ephPub)
+		if !ok {
+			return nil, errors.New("ecies: invalid ephemeral key")
+		}
+		recipient = marshalPoint(ec, priv.X, priv.Y)
+		sx, _ := ec.ScalarMult(ex, ey, priv.D.Bytes())
+		shared = sx.FillBytes(make([]byte, byteLen(ec)))
+	default:
+		return nil, fmt.Errorf("ecies: unsupported private key type %T", priv)
+	}
+	aead, err := newAEAD(shared, ephPub, recipient)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Open(nil, nonce, ciphertext[len(ephPub):], aad)
+}
+
+// ----- //
+
+func newAEAD(shared, ephPub, recipient []byte) (cipher.AEAD, error) {
+	info := append(append(append([]byte{}, kdfInfo...), ephPub...), recipient...)
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, nil, info), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// x25519PublicKey maps an Ed25519 public key to the X25519 key of the same secret, u = (1 + y) / (1 - y)
+func x25519PublicKey(pub ed25519.PublicKey) ([]byte, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("ecies: invalid ed25519 public key")
+	}
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	le := append([]byte{}, pub...)
+	le[31] &= 0x7f
+	y := new(big.Int).SetBytes(reverse(le))
+	one := big.NewInt(1)
+	den := new(big.Int).Mod(new(big.Int).Sub(one, y), p)
+	if p.Cmp(y) <= 0 || den.Sign() == 0 {
+		return nil, errors.New("ecies: invalid ed25519 public key")
+	}
+	u := new(big.Int).Mul(new(big.Int).Add(one, y), new(big.Int).ModInverse(den, p))
+	u.Mod(u, p)
+	return reverse(u.FillBytes(make([]byte, curve25519.PointSize))), nil
+}
+
+func reverse(bz []byte) []byte {
+	for i, j := 0, len(bz)-1; i < j; i, j = i+1, j-1 {
+		bz[i], bz[j] = bz[j], bz[i]
+	}
+	return bz
+}
+
+func byteLen(ec elliptic.Curve) int {
+	return (ec.Params().BitSize + 7) / 8
+}
+
+// marshalPoint encodes a point in uncompressed form; elliptic.Marshal is not used as it is deprecated for custom curves
+func marshalPoint(ec elliptic.Curve, x, y *big.Int) []byte {
+	n := byteLen(ec)
+	bz := make([]byte, 1+2*n)
+	bz[0] = 4
+	x.FillBytes(bz[1 : 1+n])
+	y.FillBytes(bz[1+n:])
+	return bz
+}
+
+func unmarshalPoint(ec elliptic.Curve, bz []byte) (x, y *big.Int, ok bool) {
+	n := byteLen(ec)
+	if len(bz) != 1+2*n || bz[0] != 4 {
+		return nil, nil, false
+	}
+	x, y = new(big.Int).SetBytes(bz[1:1+n]), new(big.Int).SetBytes(bz[1+n:])
+	if P := ec.Params().P; P.Cmp(x) <= 0 || P.Cmp(y) <= 0 || !ec.IsOnCurve(x, y) {
+		return nil, nil, false
+	}
+	return x, y, true
+}
@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ecies_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/crypto/ecies"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	s256, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	assert.NoError(t, err)
+
+	plaintext, aad := []byte("a secret share"), []byte("from P1 to P2")
+	for _, keys := range []struct {
+		pub  crypto.PublicKey
+		priv crypto.PrivateKey
+	}{
+		{edPub, edPriv},
+		{&p256.PublicKey, p256},
+		{&s256.PublicKey, s256},
+	} {
+		ct, err := Encrypt(rand.Reader, keys.pub, plaintext, aad)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(ct), len(plaintext)+MaxOverhead)
+
+		pt, err := Decrypt(keys.priv, ct, aad)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, pt)
+
+		// every encryption uses a fresh ephemeral key
+		ct2, err := Encrypt(rand.Reader, keys.pub, plaintext, aad)
+		assert.NoError(t, err)
+		assert.NotEqual(t, ct, ct2)
+
+		_, err = Decrypt(keys.priv, ct, []byte("from P1 to P3"))
+		assert.Error(t, err, "the aad must match")
+		ct[len(ct)-1] ^= 1
+		_, err = Decrypt(keys.priv, ct, aad)
+		assert.Error(t, err, "a tampered ciphertext must not open")
+	}
+}
+
+func TestDecryptWithWrongKey(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	ct, err := Encrypt(rand.Reader, edPub, []byte("a secret share"), nil)
+	assert.NoError(t, err)
+	_, err = Decrypt(otherPriv, ct, nil)
+	assert.Error(t, err)
+
+	_, err = Encrypt(rand.Reader, "not a key", []byte("a secret share"), nil)
+	assert.Error(t, err)
+}
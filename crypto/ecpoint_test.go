@@ -7,6 +7,7 @@
 package crypto_test
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
@@ -165,3 +166,39 @@ func TestEdwardsEcpointJsonSerialization(t *testing.T) {
 	assert.True(t, point.Equals(&umpoint))
 	assert.True(t, reflect.TypeOf(point.Curve()) == reflect.TypeOf(umpoint.Curve()))
 }
+
+func TestP256EcpointJsonSerialization(t *testing.T) {
+	ec := tss.P256()
+
+	point := ScalarBaseMult(ec, big.NewInt(12345))
+	bz, err := json.Marshal(point)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bz), `"Curve":"P-256"`)
+
+	var umpoint ECPoint
+	err = json.Unmarshal(bz, &umpoint)
+	assert.NoError(t, err)
+
+	assert.True(t, point.Equals(&umpoint))
+	assert.Equal(t, ec, umpoint.Curve())
+}
+
+func TestCurveNameDistinguishesNISTCurves(t *testing.T) {
+	name, ok := tss.GetCurveName(elliptic.P256())
+	assert.True(t, ok)
+	assert.Equal(t, tss.NISTP256, name)
+
+	// P-384 shares its Go type with P-256 but is not registered
+	_, ok = tss.GetCurveName(elliptic.P384())
+	assert.False(t, ok)
+	assert.False(t, tss.SameCurve(elliptic.P256(), elliptic.P384()))
+
+	name, ok = tss.GetCurveName(tss.S256())
+	assert.True(t, ok)
+	assert.Equal(t, tss.Secp256k1, name)
+
+	// a new instance of a registered curve is found as well
+	name, ok = tss.GetCurveName(edwards.Edwards())
+	assert.True(t, ok)
+	assert.Equal(t, tss.Ed25519, name)
+}
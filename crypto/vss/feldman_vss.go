@@ -57,6 +57,31 @@ func CheckIndexes(ec elliptic.Curve, indexes []*big.Int) ([]*big.Int, error) {
 	return indexes, nil
 }
 
+// ShareIndex checks that the share indexes are valid and that every one of `holders` has a share, and returns the
+// position of the share of index `id` among them
+func ShareIndex(ec elliptic.Curve, indexes, holders []*big.Int, id *big.Int) (int, error) {
+	positions := make(map[string]int, len(indexes))
+	for j, index := range indexes {
+		if index == nil {
+			return -1, fmt.Errorf("the index of share %d is missing", j)
+		}
+		positions[index.String()] = j
+	}
+	if _, err := CheckIndexes(ec, indexes); err != nil {
+		return -1, err
+	}
+	for _, holder := range holders {
+		if _, ok := positions[holder.String()]; !ok {
+			return -1, fmt.Errorf("no share has the index %s", holder)
+		}
+	}
+	j, ok := positions[id.String()]
+	if !ok {
+		return -1, fmt.Errorf("no share has the index %s", id)
+	}
+	return j, nil
+}
+
 // Returns a new array of secret shares created by Shamir's Secret Sharing Algorithm,
 // requiring a minimum number of shares to recreate, of length shares, from the input secret
 func Create(ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.Int, rand io.Reader) (Vs, Shares, error) {
@@ -145,6 +170,53 @@ func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error)
 	return secret, nil
 }
 
+// CheckPublicShares checks that the public shares bigXs = x_j*G of the parties with the given indexes lie on a polynomial
+// of degree `threshold` whose value at 0 is `pub`, so that any threshold+1 of them interpolate to `pub`
+func CheckPublicShares(ec elliptic.Curve, threshold int, indexes []*big.Int, bigXs []*crypto.ECPoint, pub *crypto.ECPoint) error {
+	if len(indexes) != len(bigXs) {
+		return fmt.Errorf("%d indexes but %d public shares", len(indexes), len(bigXs))
+	}
+	if threshold < 1 || len(indexes) <= threshold {
+		return ErrNumSharesBelowThreshold
+	}
+	if _, err := CheckIndexes(ec, indexes); err != nil {
+		return err
+	}
+	// the first `threshold` shares and the value at 0 fix the polynomial; every other share must lie on it, which holds
+	// iff the first `threshold` shares and that share interpolate to `pub`
+	modN := common.ModInt(ec.Params().N)
+	set := make([]int, threshold+1)
+	for k := range set[:threshold] {
+		set[k] = k
+	}
+	for j := threshold; j < len(indexes); j++ {
+		set[threshold] = j
+		var sum *crypto.ECPoint
+		for _, k := range set {
+			lambda := one
+			for _, m := range set {
+				if m == k {
+					continue
+				}
+				lambda = modN.Mul(lambda, modN.Mul(indexes[m], modN.ModInverse(modN.Sub(indexes[m], indexes[k]))))
+			}
+			term := bigXs[k].ScalarMult(lambda)
+			if sum == nil {
+				sum = term
+				continue
+			}
+			var err error
+			if sum, err = sum.Add(term); err != nil {
+				return err
+			}
+		}
+		if !sum.Equals(pub) {
+			return fmt.Errorf("the public share of index %d does not interpolate to the public key", j)
+		}
+	}
+	return nil
+}
+
 func samplePolynomial(ec elliptic.Curve, threshold int, secret *big.Int, rand io.Reader) []*big.Int {
 	q := ec.Params().N
 	v := make([]*big.Int, threshold+1)
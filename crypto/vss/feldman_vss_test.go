@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
 	. "github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
@@ -115,3 +116,51 @@ func TestReconstruct(t *testing.T) {
 	assert.NoError(t, err4)
 	assert.NotZero(t, secret4)
 }
+
+func TestCheckPublicShares(t *testing.T) {
+	num, threshold := 5, 3
+
+	secret := common.GetRandomPositiveInt(rand.Reader, tss.EC().Params().N)
+	pub := crypto.ScalarBaseMult(tss.EC(), secret)
+
+	ids := make([]*big.Int, 0)
+	for i := 0; i < num; i++ {
+		ids = append(ids, common.GetRandomPositiveInt(rand.Reader, tss.EC().Params().N))
+	}
+
+	_, shares, err := Create(tss.EC(), threshold, secret, ids, rand.Reader)
+	assert.NoError(t, err)
+	bigXs := make([]*crypto.ECPoint, num)
+	for i, share := range shares {
+		bigXs[i] = crypto.ScalarBaseMult(tss.EC(), share.Share)
+	}
+	assert.NoError(t, CheckPublicShares(tss.EC(), threshold, ids, bigXs, pub))
+
+	// a single wrong share is caught, wherever it is
+	for i := range bigXs {
+		wrong := append([]*crypto.ECPoint{}, bigXs...)
+		wrong[i] = crypto.ScalarBaseMult(tss.EC(), common.GetRandomPositiveInt(rand.Reader, tss.EC().Params().N))
+		assert.Error(t, CheckPublicShares(tss.EC(), threshold, ids, wrong, pub))
+	}
+	assert.Error(t, CheckPublicShares(tss.EC(), threshold, ids, bigXs, bigXs[0]))
+	assert.Error(t, CheckPublicShares(tss.EC(), threshold-1, ids, bigXs, pub), "the shares are of a higher degree")
+	assert.ErrorIs(t, CheckPublicShares(tss.EC(), num, ids, bigXs, pub), ErrNumSharesBelowThreshold)
+}
+
+func TestShareIndex(t *testing.T) {
+	ec := tss.EC()
+	indexes := []*big.Int{big.NewInt(3), big.NewInt(1), big.NewInt(2)}
+
+	j, err := ShareIndex(ec, indexes, []*big.Int{big.NewInt(1), big.NewInt(2)}, big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, j)
+
+	_, err = ShareIndex(ec, indexes, []*big.Int{big.NewInt(4)}, big.NewInt(2))
+	assert.Error(t, err, "a holder without a share")
+	_, err = ShareIndex(ec, indexes, nil, big.NewInt(4))
+	assert.Error(t, err, "an unknown share")
+	_, err = ShareIndex(ec, []*big.Int{big.NewInt(1), big.NewInt(1)}, nil, big.NewInt(1))
+	assert.Error(t, err, "duplicate indexes")
+	_, err = ShareIndex(ec, []*big.Int{big.NewInt(1), nil}, nil, big.NewInt(1))
+	assert.Error(t, err, "a missing index")
+}
@@ -7,11 +7,11 @@
 package keygen
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
@@ -100,7 +100,11 @@ func (p *LocalParty) FirstRound() tss.Round {
 }
 
 func (p *LocalParty) Start() *tss.Error {
-	return tss.BaseStart(p, TaskName)
+	return p.StartWithContext(context.Background())
+}
+
+func (p *LocalParty) StartWithContext(ctx context.Context) *tss.Error {
+	return tss.BaseStartWithContext(ctx, p, TaskName)
 }
 
 func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
@@ -115,10 +119,22 @@ func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroa
 	return p.Update(msg)
 }
 
+func (p *LocalParty) UpdateFromBytesWithRouting(wireBytes []byte, routing *tss.MessageRouting) (bool, *tss.Error) {
+	return tss.UpdateFromBytesWithRouting(p, wireBytes, routing)
+}
+
 func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
+	// check that the message was signed by the identity key of its sender
+	if err := p.params.VerifyMessage(msg); err != nil {
+		return false, p.WrapError(err)
+	}
+	// check the integers of the message against the curve of the session
+	if err := p.params.ValidateCurve(msg); err != nil {
+		return false, p.WrapError(err, msg.GetFrom())
+	}
 	// check that the message's "from index" will fit into the array
 	if maxFromIdx := p.params.PartyCount() - 1; maxFromIdx < msg.GetFrom().Index {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
@@ -132,24 +148,24 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
-	fromPIdx := msg.GetFrom().Index
-
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// identical replays are ignored and a conflicting message from the same sender is blamed as equivocation.
+	// we still expect the caller to apply spoofing protection.
+	var store []tss.ParsedMessage
 	switch msg.Content().(type) {
 	case *KGRound1Message:
-		p.temp.kgRound1Messages[fromPIdx] = msg
+		store = p.temp.kgRound1Messages
 	case *KGRound2Message1:
-		p.temp.kgRound2Message1s[fromPIdx] = msg
+		store = p.temp.kgRound2Message1s
 	case *KGRound2Message2:
-		p.temp.kgRound2Message2s[fromPIdx] = msg
+		store = p.temp.kgRound2Message2s
 	case *KGRound3Message:
-		p.temp.kgRound3Messages[fromPIdx] = msg
+		store = p.temp.kgRound3Messages
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warningf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
-	return true, nil
+	return p.StoreMessageOnce(store, msg)
 }
 
 // recovers a party's original index in the set of parties during keygen
@@ -8,14 +8,14 @@ package keygen
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/json"
-	"fmt"
 	"math/big"
 	"os"
 	"runtime"
-	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
@@ -23,9 +23,11 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	dlnproof "github.com/bnb-chain/tss-lib/v2/crypto/dlnproofc"
+	"github.com/bnb-chain/tss-lib/v2/crypto/facproof"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/test/simulator"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -166,180 +168,318 @@ func TestBadMessageCulprits(t *testing.T) {
 		err2.Error())
 }
 
+func TestOversizedMessageCulprits(t *testing.T) {
+	setUp("debug")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[0], len(pIDs), 1)
+	lp := NewLocalParty(params, make(chan tss.Message, len(pIDs)), nil, fixtures[0].LocalPreParams).(*LocalParty)
+	if err := lp.Start(); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	// a well-formed message whose Paillier modulus is far larger than any that keygen accepts
+	peer := fixtures[1]
+	hugeN := new(big.Int).Lsh(peer.PaillierSK.N, 64*MaxPaillierBits)
+	dlnProof := &dlnproof.Proof{}
+	for i := range dlnProof.Alpha {
+		dlnProof.Alpha[i], dlnProof.T[i] = peer.H1i, peer.H2i
+	}
+	msg, err := NewKGRound1Message(pIDs[1], big.NewInt(1), &paillier.PublicKey{N: hugeN}, peer.NTildei, peer.H1i, peer.H2i, dlnProof, dlnProof)
+	assert.NoError(t, err)
+	assert.False(t, msg.ValidateBasic())
+
+	ok, err2 := lp.Update(msg)
+	assert.False(t, ok)
+	if !assert.Error(t, err2) {
+		return
+	}
+	assert.Equal(t, []*tss.PartyID{pIDs[1]}, err2.Culprits())
+	assert.Contains(t, err2.Error(), "message failed ValidateBasic")
+
+	// the same message with a modulus of the accepted size passes
+	msg, err = NewKGRound1Message(pIDs[1], big.NewInt(1), &peer.PaillierSK.PublicKey, peer.NTildei, peer.H1i, peer.H2i, dlnProof, dlnProof)
+	assert.NoError(t, err)
+	assert.True(t, msg.ValidateBasic())
+}
+
+func TestValidateShare(t *testing.T) {
+	keys, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := func(i int) *tss.Parameters {
+		return tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+	}
+	for i := range keys {
+		assert.NoError(t, keys[i].ValidateShare(params(i)))
+	}
+	// a subset of the parties may use the key, as for signing
+	signers := pIDs[:testThreshold+1]
+	assert.NoError(t, keys[0].ValidateShare(tss.NewParameters(tss.S256(), tss.NewPeerContext(signers), pIDs[0], len(signers), testThreshold)))
+
+	assert.Error(t, keys[0].ValidateShare(params(1)), "the share of another party")
+	assert.Error(t, keys[0].ValidateShare(tss.NewParameters(tss.S256(), p2pCtx, pIDs[0], len(pIDs), testThreshold-1)),
+		"another threshold")
+
+	broken := func(mutate func(key *LocalPartySaveData)) LocalPartySaveData {
+		keys, _, err := LoadKeygenTestFixtures(1)
+		assert.NoError(t, err)
+		mutate(&keys[0])
+		return keys[0]
+	}
+	i := 0
+	for j, Pj := range pIDs {
+		if Pj.KeyInt().Cmp(keys[0].ShareID) == 0 {
+			i = j
+		}
+	}
+	cases := map[string]func(key *LocalPartySaveData){
+		"wrong secret share": func(key *LocalPartySaveData) {
+			key.Xi = new(big.Int).Add(key.Xi, big.NewInt(1))
+		},
+		"wrong public share": func(key *LocalPartySaveData) {
+			key.BigXj[(i+1)%len(key.BigXj)] = crypto.ScalarBaseMult(tss.S256(), big.NewInt(1))
+		},
+		"wrong public key": func(key *LocalPartySaveData) {
+			key.ECDSAPub = crypto.ScalarBaseMult(tss.S256(), big.NewInt(1))
+		},
+		"duplicate party key": func(key *LocalPartySaveData) {
+			key.Ks[(i+1)%len(key.Ks)] = key.Ks[(i+2)%len(key.Ks)]
+		},
+		"wrong Paillier key": func(key *LocalPartySaveData) {
+			key.PaillierPKs[i] = key.PaillierPKs[(i+1)%len(key.PaillierPKs)]
+		},
+		"wrong NTilde": func(key *LocalPartySaveData) {
+			key.NTildej[i] = key.NTildej[(i+1)%len(key.NTildej)]
+		},
+		"wrong h2": func(key *LocalPartySaveData) {
+			key.H2i = key.H1j[(i+1)%len(key.H1j)]
+			key.H2j[i] = key.H2i
+		},
+		"missing party": func(key *LocalPartySaveData) {
+			key.Ks = key.Ks[:len(key.Ks)-1]
+		},
+		"wrong Paillier lambda": func(key *LocalPartySaveData) {
+			key.PaillierSK.LambdaN = new(big.Int).Add(key.PaillierSK.LambdaN, big.NewInt(1))
+		},
+		"h1 not a unit": func(key *LocalPartySaveData) {
+			// 2P+1 is a factor of NTildei
+			key.H1i = new(big.Int).Add(new(big.Int).Lsh(key.P, 1), big.NewInt(1))
+			key.H1j[i] = key.H1i
+		},
+		"short NTilde": func(key *LocalPartySaveData) {
+			j := (i + 1) % len(key.NTildej)
+			key.NTildej[j] = new(big.Int).Rsh(key.NTildej[j], 8)
+			key.H1j[j], key.H2j[j] = big.NewInt(2), big.NewInt(3)
+		},
+	}
+	for name, mutate := range cases {
+		key := broken(mutate)
+		assert.Error(t, key.ValidateShare(params(i)), name)
+	}
+}
+
+func TestEncryptedShares(t *testing.T) {
+	session := test.NewSession(tss.S256(), tss.GenerateTestPartyIDs(3), 1,
+		func(_ int, params *tss.Parameters) { params.SetEncryptShares() })
+	assert.NoError(t, session.SetIdentityKeys())
+	pIDs := session.PartyIDs
+
+	one := big.NewInt(1)
+	proof := &facproof.ProofFac{P: one, Q: one, A: one, B: one, T: one, Sigma: one, Z1: one, Z2: one, W1: one, W2: one, V: one}
+	share := common.GetRandomPositiveInt(rand.Reader, tss.S256().Params().N)
+	msg, err := NewEncryptedKGRound2Message1(session.Params[0], pIDs[1], &vss.Share{Share: share}, proof)
+	assert.NoError(t, err)
+
+	// the share is only in the message encrypted, next to the proof in the clear
+	bz, routing, err := msg.WireBytes()
+	assert.NoError(t, err)
+	parsed, err := tss.ParseWireMessageWithRouting(bz, routing)
+	assert.NoError(t, err)
+	r2msg1 := parsed.Content().(*KGRound2Message1)
+	assert.True(t, r2msg1.ValidateBasic())
+	assert.Empty(t, r2msg1.GetShare())
+	assert.NotEmpty(t, r2msg1.GetEncryptedShare())
+	assert.Len(t, r2msg1.GetFacProof(), facproof.ProofFacBytesParts)
+
+	// only the recipient can open it
+	opened, err := r2msg1.OpenShare(session.Params[1], pIDs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, share, opened)
+	_, err = r2msg1.OpenShare(session.Params[2], pIDs[0])
+	assert.Error(t, err)
+	// and as sent by its sender only
+	_, err = r2msg1.OpenShare(session.Params[1], pIDs[2])
+	assert.Error(t, err)
+
+	// a share in the clear is refused
+	plain := NewKGRound2Message1(pIDs[1], pIDs[0], &vss.Share{Share: share}, proof)
+	_, err = plain.Content().(*KGRound2Message1).OpenShare(session.Params[1], pIDs[0])
+	assert.Error(t, err)
+}
+
 func TestE2EConcurrentAndSaveFixtures(t *testing.T) {
 	setUp("info")
+	testE2EConcurrentAndSaveFixtures(t, tss.S256(), false)
+}
+
+func TestE2EConcurrentAndSaveFixturesP256(t *testing.T) {
+	setUp("info")
+	testE2EConcurrentAndSaveFixtures(t, tss.P256(), false)
+}
 
-	// tss.SetCurve(elliptic.P256())
+func TestE2EConcurrentWithProofs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the keygen with the Paillier modulus and factor proofs in short mode")
+	}
+	setUp("info")
+	testE2EConcurrentAndSaveFixtures(t, tss.S256(), true)
+}
 
+func testE2EConcurrentAndSaveFixtures(t *testing.T, ec elliptic.Curve, withProofs bool) {
 	threshold := testThreshold
-	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	fixtures, pIDs, err := LoadKeygenTestFixturesForCurve(ec, testParticipants)
 	if err != nil {
-		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
-		pIDs = tss.GenerateTestPartyIDs(testParticipants)
+		// the pre-params do not depend on the curve, so those of the secp256k1 fixtures are reused when possible
+		if fixtures, pIDs, err = LoadKeygenTestFixtures(testParticipants); err != nil {
+			common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+			pIDs = tss.GenerateTestPartyIDs(testParticipants)
+		}
 	}
 
 	p2pCtx := tss.NewPeerContext(pIDs)
 	parties := make([]*LocalParty, 0, len(pIDs))
-
-	errCh := make(chan *tss.Error, len(pIDs))
-	outCh := make(chan tss.Message, len(pIDs))
 	endCh := make(chan *LocalPartySaveData, len(pIDs))
 
-	updater := test.SharedPartyUpdater
-
 	startGR := runtime.NumGoroutine()
 
-	// init the parties
-	for i := 0; i < len(pIDs); i++ {
+	// PHASE: keygen
+	// messages arrive late, out of order and twice
+	cfg := simulator.Config{Seed: 1, Latency: 10 * time.Millisecond, Jitter: 50 * time.Millisecond, DuplicateRate: 0.2}
+	_, err = simulator.Simulate(cfg, len(pIDs), func(i int, out chan<- tss.Message) tss.Party {
 		var P *LocalParty
-		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), threshold)
-		// do not use in untrusted setting
-		params.SetNoProofMod()
-		// do not use in untrusted setting
-		params.SetNoProofFac()
+		params := tss.NewParameters(ec, p2pCtx, pIDs[i], len(pIDs), threshold)
+		if !withProofs {
+			// do not use in untrusted setting
+			params.SetNoProofMod()
+			// do not use in untrusted setting
+			params.SetNoProofFac()
+		}
 		if i < len(fixtures) {
-			P = NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+			P = NewLocalParty(params, out, endCh, fixtures[i].LocalPreParams).(*LocalParty)
 		} else {
-			P = NewLocalParty(params, outCh, endCh).(*LocalParty)
+			P = NewLocalParty(params, out, endCh).(*LocalParty)
 		}
 		parties = append(parties, P)
-		go func(P *LocalParty) {
-			if err := P.Start(); err != nil {
-				errCh <- err
-			}
-		}(P)
+		return P
+	})
+	if !assert.NoError(t, err) {
+		return
 	}
-
-	// PHASE: keygen
-	var ended int32
-keygen:
-	for {
-		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
-		select {
-		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
-			assert.FailNow(t, err.Error())
-			break keygen
-
-		case msg := <-outCh:
-			dest := msg.GetTo()
-			if dest == nil { // broadcast!
-				for _, P := range parties {
-					if P.PartyID().Index == msg.GetFrom().Index {
-						continue
-					}
-					go updater(P, msg, errCh)
-				}
-			} else { // point-to-point!
-				if dest[0].Index == msg.GetFrom().Index {
-					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
-					return
-				}
-				go updater(parties[dest[0].Index], msg, errCh)
-			}
-
-		case save := <-endCh:
-			// SAVE a test fixture file for this P (if it doesn't already exist)
-			// .. here comes a workaround to recover this party's index (it was removed from save data)
-			index, err := save.OriginalIndex()
-			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
-			tryWriteTestFixtureFile(t, index, *save)
-
-			atomic.AddInt32(&ended, 1)
-			if atomic.LoadInt32(&ended) == int32(len(pIDs)) {
-				t.Logf("Done. Received save data from %d participants", ended)
-
-				// combine shares for each Pj to get u
-				u := new(big.Int)
-				for j, Pj := range parties {
-					pShares := make(vss.Shares, 0)
-					for _, P := range parties {
-						vssMsgs := P.temp.kgRound2Message1s
-						share := vssMsgs[j].Content().(*KGRound2Message1).Share
-						shareStruct := &vss.Share{
-							Threshold: threshold,
-							ID:        P.PartyID().KeyInt(),
-							Share:     new(big.Int).SetBytes(share),
-						}
-						pShares = append(pShares, shareStruct)
-					}
-					uj, err := pShares[:threshold+1].ReConstruct(tss.S256())
-					assert.NoError(t, err, "vss.ReConstruct should not throw error")
-
-					// uG test: u*G[j] == V[0]
-					assert.Equal(t, uj, Pj.temp.ui)
-					uG := crypto.ScalarBaseMult(tss.EC(), uj)
-					assert.True(t, uG.Equals(Pj.temp.vs[0]), "ensure u*G[j] == V_0")
-
-					// xj tests: BigXj == xj*G
-					xj := Pj.data.Xi
-					gXj := crypto.ScalarBaseMult(tss.EC(), xj)
-					BigXj := Pj.data.BigXj[j]
-					assert.True(t, BigXj.Equals(gXj), "ensure BigX_j == g^x_j")
-
-					// fails if threshold cannot be satisfied (bad share)
-					{
-						badShares := pShares[:threshold]
-						badShares[len(badShares)-1].Share.Set(big.NewInt(0))
-						uj, err := pShares[:threshold].ReConstruct(tss.S256())
-						assert.NoError(t, err)
-						assert.NotEqual(t, parties[j].temp.ui, uj)
-						BigXjX, BigXjY := tss.EC().ScalarBaseMult(uj.Bytes())
-						assert.NotEqual(t, BigXjX, Pj.temp.vs[0].X())
-						assert.NotEqual(t, BigXjY, Pj.temp.vs[0].Y())
-					}
-					u = new(big.Int).Add(u, uj)
-				}
-
-				// build ecdsa key pair
-				pkX, pkY := save.ECDSAPub.X(), save.ECDSAPub.Y()
-				pk := ecdsa.PublicKey{
-					Curve: tss.EC(),
-					X:     pkX,
-					Y:     pkY,
-				}
-				sk := ecdsa.PrivateKey{
-					PublicKey: pk,
-					D:         u,
-				}
-				// test pub key, should be on curve and match pkX, pkY
-				assert.True(t, sk.IsOnCurve(pkX, pkY), "public key must be on curve")
-
-				// public key tests
-				assert.NotZero(t, u, "u should not be zero")
-				ourPkX, ourPkY := tss.EC().ScalarBaseMult(u.Bytes())
-				assert.Equal(t, pkX, ourPkX, "pkX should match expected pk derived from u")
-				assert.Equal(t, pkY, ourPkY, "pkY should match expected pk derived from u")
-				t.Log("Public key tests done.")
-
-				// make sure everyone has the same ECDSA public key
-				for _, Pj := range parties {
-					assert.Equal(t, pkX, Pj.data.ECDSAPub.X())
-					assert.Equal(t, pkY, Pj.data.ECDSAPub.Y())
-				}
-				t.Log("Public key distribution test done.")
-
-				// test sign/verify
-				data := make([]byte, 32)
-				for i := range data {
-					data[i] = byte(i)
-				}
-				r, s, err := ecdsa.Sign(rand.Reader, &sk, data)
-				assert.NoError(t, err, "sign should not throw an error")
-				ok := ecdsa.Verify(&pk, data, r, s)
-				assert.True(t, ok, "signature should be ok")
-				t.Log("ECDSA signing test done.")
-
-				t.Logf("Start goroutines: %d, End goroutines: %d", startGR, runtime.NumGoroutine())
-
-				break keygen
+	assert.Len(t, endCh, len(pIDs))
+	close(endCh)
+	var save *LocalPartySaveData
+	for save = range endCh {
+		// SAVE a test fixture file for this P (if it doesn't already exist)
+		// .. here comes a workaround to recover this party's index (it was removed from save data)
+		index, err := save.OriginalIndex()
+		assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+		tryWriteTestFixtureFile(t, ec, index, *save)
+	}
+	t.Logf("Done. Received save data from %d participants", len(parties))
+
+	// combine shares for each Pj to get u
+	u := new(big.Int)
+	for j, Pj := range parties {
+		pShares := make(vss.Shares, 0)
+		for _, P := range parties {
+			vssMsgs := P.temp.kgRound2Message1s
+			share := vssMsgs[j].Content().(*KGRound2Message1).Share
+			shareStruct := &vss.Share{
+				Threshold: threshold,
+				ID:        P.PartyID().KeyInt(),
+				Share:     new(big.Int).SetBytes(share),
 			}
+			pShares = append(pShares, shareStruct)
 		}
+		uj, err := pShares[:threshold+1].ReConstruct(ec)
+		assert.NoError(t, err, "vss.ReConstruct should not throw error")
+
+		// uG test: u*G[j] == V[0]
+		assert.Equal(t, uj, Pj.temp.ui)
+		uG := crypto.ScalarBaseMult(ec, uj)
+		assert.True(t, uG.Equals(Pj.temp.vs[0]), "ensure u*G[j] == V_0")
+
+		// xj tests: BigXj == xj*G
+		xj := Pj.data.Xi
+		gXj := crypto.ScalarBaseMult(ec, xj)
+		BigXj := Pj.data.BigXj[j]
+		assert.True(t, BigXj.Equals(gXj), "ensure BigX_j == g^x_j")
+
+		// fails if threshold cannot be satisfied (bad share)
+		{
+			badShares := pShares[:threshold]
+			badShares[len(badShares)-1].Share.Set(big.NewInt(0))
+			uj, err := pShares[:threshold].ReConstruct(ec)
+			assert.NoError(t, err)
+			assert.NotEqual(t, parties[j].temp.ui, uj)
+			BigXjX, BigXjY := ec.ScalarBaseMult(uj.Bytes())
+			assert.NotEqual(t, BigXjX, Pj.temp.vs[0].X())
+			assert.NotEqual(t, BigXjY, Pj.temp.vs[0].Y())
+		}
+		u = new(big.Int).Add(u, uj)
+	}
+	// crypto/ecdsa rejects private keys that are not reduced on the NIST curves
+	u.Mod(u, ec.Params().N)
+
+	// build ecdsa key pair
+	pkX, pkY := save.ECDSAPub.X(), save.ECDSAPub.Y()
+	pk := ecdsa.PublicKey{
+		Curve: ec,
+		X:     pkX,
+		Y:     pkY,
+	}
+	sk := ecdsa.PrivateKey{
+		PublicKey: pk,
+		D:         u,
+	}
+	// test pub key, should be on curve and match pkX, pkY
+	assert.True(t, sk.IsOnCurve(pkX, pkY), "public key must be on curve")
+
+	// public key tests
+	assert.NotZero(t, u, "u should not be zero")
+	ourPkX, ourPkY := ec.ScalarBaseMult(u.Bytes())
+	assert.Equal(t, pkX, ourPkX, "pkX should match expected pk derived from u")
+	assert.Equal(t, pkY, ourPkY, "pkY should match expected pk derived from u")
+	t.Log("Public key tests done.")
+
+	// make sure everyone has the same ECDSA public key
+	for _, Pj := range parties {
+		assert.Equal(t, pkX, Pj.data.ECDSAPub.X())
+		assert.Equal(t, pkY, Pj.data.ECDSAPub.Y())
 	}
+	t.Log("Public key distribution test done.")
+
+	// test sign/verify
+	data := make([]byte, 32)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, &sk, data)
+	assert.NoError(t, err, "sign should not throw an error")
+	ok := ecdsa.Verify(&pk, data, r, s)
+	assert.True(t, ok, "signature should be ok")
+	t.Log("ECDSA signing test done.")
+
+	t.Logf("Start goroutines: %d, End goroutines: %d", startGR, runtime.NumGoroutine())
 }
 
-func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
-	fixtureFileName := makeTestFixtureFilePath(index)
+func tryWriteTestFixtureFile(t *testing.T, ec elliptic.Curve, index int, data LocalPartySaveData) {
+	fixtureFileName := makeTestFixtureFilePath(ec, index)
 
 	// fixture file does not already exist?
 	// if it does, we won't re-create it here
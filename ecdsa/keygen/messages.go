@@ -7,8 +7,12 @@
 package keygen
 
 import (
+	"crypto/elliptic"
+	"errors"
 	"math/big"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/bnb-chain/tss-lib/v2/crypto/facproof"
 	"github.com/bnb-chain/tss-lib/v2/crypto/modproof"
 
@@ -69,14 +73,14 @@ func NewKGRound1Message(
 
 func (m *KGRound1Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.GetCommitment()) &&
-		common.NonEmptyBytes(m.GetPaillierN()) &&
-		common.NonEmptyBytes(m.GetNTilde()) &&
-		common.NonEmptyBytes(m.GetH1()) &&
-		common.NonEmptyBytes(m.GetH2()) &&
+		common.NonEmptyBytesMaxBits(m.GetCommitment(), MaxCommitmentBits()) &&
+		common.NonEmptyBytesMaxBits(m.GetPaillierN(), MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.GetNTilde(), MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.GetH1(), MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.GetH2(), MaxPaillierBits) &&
 		// expected len of dln proof = sizeof(int64) + len(alpha) + len(t)
-		common.NonEmptyMultiBytes(m.GetDlnproof_1(), 2+(dlnproof.Iterations*2)) &&
-		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2))
+		common.NonEmptyMultiBytesMaxBits(m.GetDlnproof_1(), MaxPaillierBits, 2+(dlnproof.Iterations*2)) &&
+		common.NonEmptyMultiBytesMaxBits(m.GetDlnproof_2(), MaxPaillierBits, 2+(dlnproof.Iterations*2))
 }
 
 func (m *KGRound1Message) UnmarshalCommitment() *big.Int {
@@ -128,9 +132,42 @@ func NewKGRound2Message1(
 	return tss.NewMessage(meta, content, msg)
 }
 
+// NewEncryptedKGRound2Message1 is NewKGRound2Message1 with the share encrypted to the identity key of `to`,
+// for parties that were given tss.Parameters.SetEncryptShares
+func NewEncryptedKGRound2Message1(
+	params *tss.Parameters,
+	to *tss.PartyID,
+	share *vss.Share,
+	proof *facproof.ProofFac,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:        params.PartyID(),
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	proofBzs := proof.Bytes()
+	content := &KGRound2Message1{
+		FacProof: proofBzs[:],
+	}
+	encShare, err := params.SealShare(to, string(proto.MessageName(content)), share.Share.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	content.EncryptedShare = encShare
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
 func (m *KGRound2Message1) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *KGRound2Message1) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.GetShare())
+		ValidShareBytes(m.GetShare(), m.GetEncryptedShare(), ec) &&
+		// the proof may be absent for backward compatibility, but it is bounded when it is there
+		len(m.GetFacProof()) <= facproof.ProofFacBytesParts &&
+		common.MultiBytesMaxBits(m.GetFacProof(), MaxFacProofBits(ec))
 	// This is commented for backward compatibility, which msg has no proof
 	// && common.NonEmptyMultiBytes(m.GetFacProof(), facproof.ProofFacBytesParts)
 }
@@ -139,6 +176,18 @@ func (m *KGRound2Message1) UnmarshalShare() *big.Int {
 	return new(big.Int).SetBytes(m.Share)
 }
 
+// OpenShare returns the share sent by `from`, decrypting it when it was encrypted to this party
+func (m *KGRound2Message1) OpenShare(params *tss.Parameters, from *tss.PartyID) (*big.Int, error) {
+	share, err := params.OpenShare(from, string(proto.MessageName(m)), m.GetShare(), m.GetEncryptedShare())
+	if err != nil {
+		return nil, err
+	}
+	if !common.NonEmptyBytesMaxBits(share, MaxScalarBits(params.EC())) {
+		return nil, errors.New("received an invalid share")
+	}
+	return new(big.Int).SetBytes(share), nil
+}
+
 func (m *KGRound2Message1) UnmarshalFacProof() (*facproof.ProofFac, error) {
 	return facproof.NewProofFromBytes(m.GetFacProof())
 }
@@ -165,8 +214,15 @@ func NewKGRound2Message2(
 }
 
 func (m *KGRound2Message2) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *KGRound2Message2) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.GetDeCommitment())
+		common.NonEmptyMultiBytesMaxBits(m.GetDeCommitment(), MaxDeCommitmentBits(ec)) &&
+		// the proof may be absent for backward compatibility, but it is bounded when it is there
+		len(m.GetModProof()) <= modproof.ProofModBytesParts &&
+		common.MultiBytesMaxBits(m.GetModProof(), MaxPaillierBits)
 	// This is commented for backward compatibility, which msg has no proof
 	// && common.NonEmptyMultiBytes(m.GetModProof(), modproof.ProofModBytesParts)
 }
@@ -206,7 +262,7 @@ func NewKGRound3Message(
 
 func (m *KGRound3Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.GetPaillierProof(), paillier.ProofIters)
+		common.NonEmptyMultiBytesMaxBits(m.GetPaillierProof(), MaxPaillierBits, paillier.ProofIters)
 }
 
 func (m *KGRound3Message) UnmarshalProofInts() paillier.Proof {
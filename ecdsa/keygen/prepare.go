@@ -17,6 +17,7 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 const (
@@ -53,6 +54,11 @@ func GeneratePreParamsWithContext(ctx context.Context, optionalConcurrency ...in
 // If not specified, a concurrency value equal to the number of available CPU cores will be used.
 // If pre-parameters could not be generated before the context is done, an error is returned.
 func GeneratePreParamsWithContextAndRandom(ctx context.Context, rand io.Reader, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return GeneratePreParamsWithLogger(ctx, rand, common.Logger, optionalConcurrency...)
+}
+
+// GeneratePreParamsWithLogger is GeneratePreParamsWithContextAndRandom writing its progress to `logger`, e.g. the Logger of a session's Parameters
+func GeneratePreParamsWithLogger(ctx context.Context, rand io.Reader, logger tss.Logger, optionalConcurrency ...int) (*LocalPreParams, error) {
 	var concurrency int
 	if 0 < len(optionalConcurrency) {
 		if 1 < len(optionalConcurrency) {
@@ -72,7 +78,7 @@ func GeneratePreParamsWithContextAndRandom(ctx context.Context, rand io.Reader,
 
 	// 4. generate Paillier public key E_i, private key and proof
 	go func(ch chan<- *paillier.PrivateKey) {
-		common.Logger.Info("generating the Paillier modulus, please wait...")
+		logger.Infof("generating the Paillier modulus, please wait...")
 		start := time.Now()
 		// more concurrency weight is assigned here because the paillier primes have a requirement of having "large" P-Q
 		PiPaillierSk, _, err := paillier.GenerateKeyPair(ctx, rand, paillierModulusLen, concurrency*2)
@@ -80,21 +86,21 @@ func GeneratePreParamsWithContextAndRandom(ctx context.Context, rand io.Reader,
 			ch <- nil
 			return
 		}
-		common.Logger.Infof("paillier modulus generated. took %s\n", time.Since(start))
+		logger.Infof("paillier modulus generated. took %s\n", time.Since(start))
 		ch <- PiPaillierSk
 	}(paiCh)
 
 	// 5-7. generate safe primes for ZKPs used later on
 	go func(ch chan<- []*common.GermainSafePrime) {
 		var err error
-		common.Logger.Info("generating the safe primes for the signing proofs, please wait...")
+		logger.Infof("generating the safe primes for the signing proofs, please wait...")
 		start := time.Now()
 		sgps, err := common.GetRandomSafePrimesConcurrent(ctx, safePrimeBitLen, 2, concurrency, rand)
 		if err != nil {
 			ch <- nil
 			return
 		}
-		common.Logger.Infof("safe primes generated. took %s\n", time.Since(start))
+		logger.Infof("safe primes generated. took %s\n", time.Since(start))
 		ch <- sgps
 	}(sgpCh)
 
@@ -108,7 +114,7 @@ consumer:
 	for {
 		select {
 		case <-logProgressTicker.C:
-			common.Logger.Info("still generating primes...")
+			logger.Infof("still generating primes...")
 		case sgps = <-sgpCh:
 			if sgps == nil ||
 				sgps[0] == nil || sgps[1] == nil ||
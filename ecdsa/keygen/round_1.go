@@ -77,7 +77,7 @@ func (round *round1) Start() *tss.Error {
 		{
 			ctx, cancel := context.WithTimeout(context.Background(), round.SafePrimeGenTimeout())
 			defer cancel()
-			preParams, err = GeneratePreParamsWithContextAndRandom(ctx, round.Rand(), round.Concurrency())
+			preParams, err = GeneratePreParamsWithLogger(ctx, round.Rand(), round.Logger(), round.Concurrency())
 			if err != nil {
 				return round.WrapError(errors.New("pre-params generation failed"), Pi)
 			}
@@ -103,7 +103,7 @@ func (round *round1) Start() *tss.Error {
 	// and keep in temporary storage:
 	// - VSS Vs
 	// - our set of Shamir shares
-	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	round.temp.ssidNonce = round.SSIDNonce()
 	round.save.ShareID = ids[i]
 	round.temp.vs = vs
 	ssid, err := round.getSSID()
@@ -126,6 +126,9 @@ func (round *round1) Start() *tss.Error {
 			return round.WrapError(err, Pi)
 		}
 		round.temp.kgRound1Messages[i] = msg
+		if err := round.StampMessage(msg); err != nil {
+			return round.WrapError(err)
+		}
 		round.out <- msg
 	}
 	return nil
@@ -15,7 +15,6 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/crypto/facproof"
 	"github.com/bnb-chain/tss-lib/v2/crypto/modproof"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -31,7 +30,7 @@ func (round *round2) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	common.Logger.Debugf(
+	round.Logger().Debugf(
 		"%s Setting up DLN verification with concurrency level of %d",
 		round.PartyID(),
 		round.Concurrency(),
@@ -79,12 +78,14 @@ func (round *round2) Start() *tss.Error {
 		_msg := msg
 
 		dlnVerifier.VerifyDLNProof1(r1msg, H1j, H2j, NTildej, func(ok bool) {
+			round.observeProof(_msg.GetFrom(), tss.ProofDLN, ok)
 			if !ok {
 				dlnProof1FailCulprits[_j] = _msg.GetFrom()
 			}
 			wg.Done()
 		})
 		dlnVerifier.VerifyDLNProof2(r1msg, H2j, H1j, NTildej, func(ok bool) {
+			round.observeProof(_msg.GetFrom(), tss.ProofDLN, ok)
 			if !ok {
 				dlnProof2FailCulprits[_j] = _msg.GetFrom()
 			}
@@ -95,7 +96,7 @@ func (round *round2) Start() *tss.Error {
 
 	for _, culprit := range append(dlnProof1FailCulprits, dlnProof2FailCulprits...) {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof failed"), culprit)
+			return round.WrapError(tss.NewProofError(tss.ProofDLN, errors.New("dln proof failed")), culprit)
 		}
 	}
 
@@ -114,17 +115,26 @@ func (round *round2) Start() *tss.Error {
 	shares := round.temp.shares
 	ContextI := append(round.temp.ssid, big.NewInt(int64(i)).Bytes()...)
 
+	// each goroutine draws from its own source, so that a seeded Rand gives the same values in every run
+	rands, err := round.ForkRand(len(round.Parties().IDs()) + 1)
+	if err != nil {
+		return round.WrapError(err)
+	}
 	var msg1Wg sync.WaitGroup
-	for j, Pj := range round.Parties().IDs() {
+	facProofs := make([]*facproof.ProofFac, len(round.Parties().IDs()))
+	for j := range round.Parties().IDs() {
 		msg1Wg.Add(1)
-		go func(j int, Pj *tss.PartyID) {
+		go func(j int) {
 			defer msg1Wg.Done()
 
-			var facProof *facproof.ProofFac
 			if round.Params().NoProofFac() {
-				facProof = &facproof.ProofFac{}
+				// an all-zero proof is sent as empty byte parts, which the receiver accepts when it also skips the proof
+				facProofs[j] = &facproof.ProofFac{
+					P: zero, Q: zero, A: zero, B: zero, T: zero, Sigma: zero,
+					Z1: zero, Z2: zero, W1: zero, W2: zero, V: zero,
+				}
 			} else {
-				facProof, _ = facproof.NewProof(
+				facProofs[j], _ = facproof.NewProof(
 					ContextI,
 					round.EC(),
 					round.save.PaillierSK.N,
@@ -133,16 +143,10 @@ func (round *round2) Start() *tss.Error {
 					round.save.H2j[j],
 					round.save.PaillierSK.P,
 					round.save.PaillierSK.Q,
-					round.Rand(),
+					rands[j],
 				)
 			}
-			r2msg1 := NewKGRound2Message1(Pj, round.PartyID(), shares[j], facProof)
-			if j == i {
-				round.temp.kgRound2Message1s[j] = r2msg1
-			} else {
-				round.out <- r2msg1
-			}
-		}(j, Pj)
+		}(j)
 	}
 
 	// MOD proof chạy song song
@@ -151,20 +155,37 @@ func (round *round2) Start() *tss.Error {
 	modDone := make(chan struct{})
 	go func() {
 		if round.Parameters.NoProofMod() {
-			modProof = &modproof.ProofMod{}
+			modProof = &modproof.ProofMod{W: zero, A: zero, B: zero}
 		} else {
 			modProof, modErr = modproof.NewProof(
 				ContextI,
 				round.save.PaillierSK.N,
 				round.save.PaillierSK.P,
 				round.save.PaillierSK.Q,
-				round.Rand(),
+				rands[len(rands)-1],
 			)
 		}
 		close(modDone)
 	}()
 
 	msg1Wg.Wait()
+	// the messages are sealed and signed in order, drawing from Rand
+	for j, Pj := range round.Parties().IDs() {
+		r2msg1 := NewKGRound2Message1(Pj, round.PartyID(), shares[j], facProofs[j])
+		if j == i {
+			round.temp.kgRound2Message1s[j] = r2msg1
+			continue
+		}
+		if round.EncryptShares() {
+			if r2msg1, err = NewEncryptedKGRound2Message1(round.Params(), Pj, shares[j], facProofs[j]); err != nil {
+				return round.WrapError(err)
+			}
+		}
+		if err := round.StampMessage(r2msg1); err != nil {
+			return round.WrapError(err)
+		}
+		round.out <- r2msg1
+	}
 	<-modDone
 	if modErr != nil {
 		return round.WrapError(modErr, round.PartyID())
@@ -172,6 +193,9 @@ func (round *round2) Start() *tss.Error {
 
 	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG, modProof)
 	round.temp.kgRound2Message2s[i] = r2msg2
+	if err := round.StampMessage(r2msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r2msg2
 
 	return nil
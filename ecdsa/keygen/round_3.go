@@ -33,12 +33,17 @@ func (round *round3) Start() *tss.Error {
 
 	// 1,9. calculate xi
 	xi := new(big.Int).Set(round.temp.shares[PIdx].Share)
-	for j := range Ps {
+	shares := make([]*big.Int, len(Ps))
+	for j, Pj := range Ps {
 		if j == PIdx {
 			continue
 		}
 		r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
-		share := r2msg1.UnmarshalShare()
+		share, err := r2msg1.OpenShare(round.Params(), Pj)
+		if err != nil {
+			return round.WrapError(err, Pj)
+		}
+		shares[j] = share
 		xi = new(big.Int).Add(xi, share)
 	}
 	round.save.Xi = new(big.Int).Mod(xi, round.Params().EC().Params().N)
@@ -87,14 +92,16 @@ func (round *round3) Start() *tss.Error {
 			if err != nil && round.Parameters.NoProofMod() {
 				// For old parties, the modProof could be not exist
 				// Not return error for compatibility reason
-				common.Logger.Warningf("modProof not exist:%s", Ps[j])
+				round.Logger().Warningf("modProof not exist:%s", Ps[j])
 			} else {
 				if err != nil {
-					ch <- vssOut{errors.New("modProof verify failed"), nil}
+					ch <- vssOut{tss.NewProofError(tss.ProofMod, errors.New("modProof verify failed")), nil}
 					return
 				}
-				if ok = modProof.Verify(ContextJ, round.save.PaillierPKs[j].N); !ok {
-					ch <- vssOut{errors.New("modProof verify failed"), nil}
+				ok = modProof.Verify(ContextJ, round.save.PaillierPKs[j].N)
+				round.observeProof(Ps[j], tss.ProofMod, ok)
+				if !ok {
+					ch <- vssOut{tss.NewProofError(tss.ProofMod, errors.New("modProof verify failed")), nil}
 					return
 				}
 			}
@@ -102,25 +109,29 @@ func (round *round3) Start() *tss.Error {
 			PjShare := vss.Share{
 				Threshold: round.Threshold(),
 				ID:        round.PartyID().KeyInt(),
-				Share:     r2msg1.UnmarshalShare(),
+				Share:     shares[j],
 			}
-			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
-				ch <- vssOut{errors.New("vss verify failed"), nil}
+			ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs)
+			round.observeProof(Ps[j], tss.ProofVSS, ok)
+			if !ok {
+				ch <- vssOut{tss.NewProofError(tss.ProofVSS, errors.New("vss verify failed")), nil}
 				return
 			}
 			facProof, err := r2msg1.UnmarshalFacProof()
 			if err != nil && round.NoProofFac() {
 				// For old parties, the facProof could be not exist
 				// Not return error for compatibility reason
-				common.Logger.Warningf("facProof not exist:%s", Ps[j])
+				round.Logger().Warningf("facProof not exist:%s", Ps[j])
 			} else {
 				if err != nil {
-					ch <- vssOut{errors.New("facProof verify failed"), nil}
+					ch <- vssOut{tss.NewProofError(tss.ProofFac, errors.New("facProof verify failed")), nil}
 					return
 				}
-				if ok = facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
-					round.save.H1i, round.save.H2i); !ok {
-					ch <- vssOut{errors.New("facProof verify failed"), nil}
+				ok = facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+					round.save.H1i, round.save.H2i)
+				round.observeProof(Ps[j], tss.ProofFac, ok)
+				if !ok {
+					ch <- vssOut{tss.NewProofError(tss.ProofFac, errors.New("facProof verify failed")), nil}
 					return
 				}
 			}
@@ -209,13 +220,16 @@ func (round *round3) Start() *tss.Error {
 	round.save.ECDSAPub = ecdsaPubKey
 
 	// PRINT public key & private share
-	common.Logger.Debugf("%s public key: %x", round.PartyID(), ecdsaPubKey)
+	round.Logger().Debugf("%s public key: %x", round.PartyID(), ecdsaPubKey)
 
 	// BROADCAST paillier proof for Pi
 	ki := round.PartyID().KeyInt()
 	proof := round.save.PaillierSK.Proof(ki, ecdsaPubKey)
 	r3msg := NewKGRound3Message(round.PartyID(), proof)
 	round.temp.kgRound3Messages[PIdx] = r3msg
+	if err := round.StampMessage(r3msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r3msg
 	return nil
 }
@@ -9,7 +9,6 @@ package keygen
 import (
 	"errors"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
@@ -42,8 +41,9 @@ func (round *round4) Start() *tss.Error {
 		go func(prf paillier.Proof, j int, ch chan<- bool) {
 			ppk := round.save.PaillierPKs[j]
 			ok, err := prf.Verify(ppk.N, PIDs[j], ecdsaPub)
+			round.observeProof(Ps[j], tss.ProofPaillier, ok && err == nil)
 			if err != nil {
-				common.Logger.Error(round.WrapError(err, Ps[j]).Error())
+				round.Logger().Errorf("%s", round.WrapError(err, Ps[j]))
 				ch <- false
 				return
 			}
@@ -63,14 +63,14 @@ func (round *round4) Start() *tss.Error {
 	for j, ok := range round.ok {
 		if !ok {
 			culprits = append(culprits, Ps[j])
-			common.Logger.Warningf("paillier verify failed for party %s", Ps[j])
+			round.Logger().Warningf("paillier verify failed for party %s", Ps[j])
 			continue
 		}
-		common.Logger.Debugf("paillier verify passed for party %s", Ps[j])
+		round.Logger().Debugf("paillier verify passed for party %s", Ps[j])
 
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("paillier verify failed"), culprits...)
+		return round.WrapError(tss.NewProofError(tss.ProofPaillier, errors.New("paillier verify failed")), culprits...)
 	}
 
 	round.end <- round.save
@@ -9,10 +9,13 @@ package keygen
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -76,6 +79,106 @@ func (preParams LocalPreParams) ValidateWithProof() bool {
 		preParams.Q != nil
 }
 
+// ValidateShare checks that the save data is a consistent share of this party of a key of the parties of `params`:
+// Xi must be the secret of the public share of this party, any threshold+1 public shares must interpolate to ECDSAPub,
+// and the Paillier key and range proof parameters of this party must match the public ones of the key.
+func (save LocalPartySaveData) ValidateShare(params *tss.Parameters) error {
+	ec := params.EC()
+	n := len(save.Ks)
+	if len(save.BigXj) != n || len(save.NTildej) != n || len(save.H1j) != n || len(save.H2j) != n || len(save.PaillierPKs) != n {
+		return errors.New("the save data does not hold the same number of values for every party")
+	}
+	if save.Xi == nil || save.ShareID == nil {
+		return errors.New("the save data has no secret share")
+	}
+	if save.ECDSAPub == nil || !save.ECDSAPub.ValidateBasic() {
+		return errors.New("the save data has no valid public key")
+	}
+
+	// the parties
+	if params.PartyID().KeyInt().Cmp(save.ShareID) != 0 {
+		return errors.New("the share belongs to another party")
+	}
+	i, err := vss.ShareIndex(ec, save.Ks, params.Parties().IDs().Keys(), save.ShareID)
+	if err != nil {
+		return fmt.Errorf("the party keys do not match the parties: %w", err)
+	}
+
+	// the secret and public shares
+	for j, Xj := range save.BigXj {
+		if Xj == nil || !Xj.ValidateBasic() {
+			return fmt.Errorf("the public share of party %d is not valid", j)
+		}
+	}
+	if !crypto.ScalarBaseMult(ec, save.Xi).Equals(save.BigXj[i]) {
+		return errors.New("the secret share does not match the public share of this party")
+	}
+	if err = vss.CheckPublicShares(ec, params.Threshold(), save.Ks, save.BigXj, save.ECDSAPub); err != nil {
+		return fmt.Errorf("the public shares do not match the public key: %w", err)
+	}
+
+	// the Paillier keys
+	for j, pk := range save.PaillierPKs {
+		if pk == nil || pk.N == nil || pk.N.Sign() <= 0 {
+			return fmt.Errorf("the Paillier public key of party %d is not valid", j)
+		}
+	}
+	sk := save.PaillierSK
+	if sk == nil || sk.N == nil || sk.LambdaN == nil || sk.PhiN == nil {
+		return errors.New("the save data has no Paillier private key")
+	}
+	if sk.N.Cmp(save.PaillierPKs[i].N) != 0 {
+		return errors.New("the Paillier private key does not match the public key of this party")
+	}
+	one := big.NewInt(1)
+	if sk.P != nil && sk.Q != nil {
+		pMinus1, qMinus1 := new(big.Int).Sub(sk.P, one), new(big.Int).Sub(sk.Q, one)
+		phiN := new(big.Int).Mul(pMinus1, qMinus1)
+		lambdaN := new(big.Int).Div(phiN, new(big.Int).GCD(nil, nil, pMinus1, qMinus1))
+		if new(big.Int).Mul(sk.P, sk.Q).Cmp(sk.N) != 0 || sk.PhiN.Cmp(phiN) != 0 || sk.LambdaN.Cmp(lambdaN) != 0 {
+			return errors.New("the Paillier private key does not match its primes")
+		}
+	} else {
+		// without its primes, check that the key decrypts (1+N)^m, the encryption of m with randomness 1
+		m := new(big.Int).Sub(sk.N, one)
+		N2 := new(big.Int).Mul(sk.N, sk.N)
+		c := new(big.Int).Exp(new(big.Int).Add(sk.N, one), m, N2)
+		if m2, err := sk.Decrypt(c); err != nil || m2.Cmp(m) != 0 {
+			return errors.New("the Paillier private key does not decrypt under its public key")
+		}
+	}
+
+	// the range proof parameters; h1j and h2j must be units of Z_NTildej
+	gcd := new(big.Int)
+	for j := range save.NTildej {
+		NTildej, H1j, H2j := save.NTildej[j], save.H1j[j], save.H2j[j]
+		if NTildej == nil || H1j == nil || H2j == nil || NTildej.BitLen() != paillierBitsLen ||
+			H1j.Cmp(one) <= 0 || H1j.Cmp(NTildej) >= 0 || H2j.Cmp(one) <= 0 || H2j.Cmp(NTildej) >= 0 || H1j.Cmp(H2j) == 0 ||
+			gcd.GCD(nil, nil, H1j, NTildej).Cmp(one) != 0 || gcd.GCD(nil, nil, H2j, NTildej).Cmp(one) != 0 {
+			return fmt.Errorf("the range proof parameters of party %d are not valid", j)
+		}
+	}
+	if save.NTildei == nil || save.H1i == nil || save.H2i == nil ||
+		save.NTildei.Cmp(save.NTildej[i]) != 0 || save.H1i.Cmp(save.H1j[i]) != 0 || save.H2i.Cmp(save.H2j[i]) != 0 {
+		return errors.New("the range proof parameters do not match the public ones of this party")
+	}
+	// P and Q are the Sophie Germain primes of the safe primes 2P+1 and 2Q+1 of NTildei
+	if save.P != nil && save.Q != nil {
+		safeP := new(big.Int).Add(new(big.Int).Lsh(save.P, 1), one)
+		safeQ := new(big.Int).Add(new(big.Int).Lsh(save.Q, 1), one)
+		if new(big.Int).Mul(safeP, safeQ).Cmp(save.NTildei) != 0 {
+			return errors.New("NTildei does not match its primes")
+		}
+	}
+	if save.Alpha != nil && save.Beta != nil {
+		modNTilde := common.ModInt(save.NTildei)
+		if modNTilde.Exp(save.H1i, save.Alpha).Cmp(save.H2i) != 0 || modNTilde.Exp(save.H2i, save.Beta).Cmp(save.H1i) != 0 {
+			return errors.New("h1i and h2i do not match alpha and beta")
+		}
+	}
+	return nil
+}
+
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
@@ -7,10 +7,10 @@
 package resharing
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
@@ -31,6 +31,9 @@ type (
 		temp        localTempData
 		input, save keygen.LocalPartySaveData
 
+		// set by RestoreLocalParty to the round that Resume continues from
+		resumeRound tss.Round
+
 		// outbound messaging
 		out chan<- tss.Message
 		end chan<- *keygen.LocalPartySaveData
@@ -108,7 +111,11 @@ func (p *LocalParty) FirstRound() tss.Round {
 }
 
 func (p *LocalParty) Start() *tss.Error {
-	return tss.BaseStart(p, TaskName)
+	return p.StartWithContext(context.Background())
+}
+
+func (p *LocalParty) StartWithContext(ctx context.Context) *tss.Error {
+	return tss.BaseStartWithContext(ctx, p, TaskName)
 }
 
 func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
@@ -123,10 +130,22 @@ func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroa
 	return p.Update(msg)
 }
 
+func (p *LocalParty) UpdateFromBytesWithRouting(wireBytes []byte, routing *tss.MessageRouting) (bool, *tss.Error) {
+	return tss.UpdateFromBytesWithRouting(p, wireBytes, routing)
+}
+
 func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
+	// check that the message was signed by the identity key of its sender
+	if err := p.params.VerifyMessage(msg); err != nil {
+		return false, p.WrapError(err)
+	}
+	// check the integers of the message against the curve of the session
+	if err := p.params.ValidateCurve(msg); err != nil {
+		return false, p.WrapError(err, msg.GetFrom())
+	}
 	// check that the message's "from index" will fit into the array
 	var maxFromIdx int
 	switch msg.Content().(type) {
@@ -147,30 +166,30 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
-	fromPIdx := msg.GetFrom().Index
-
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// identical replays are ignored and a conflicting message from the same sender is blamed as equivocation.
+	// we still expect the caller to apply spoofing protection.
+	var store []tss.ParsedMessage
 	switch msg.Content().(type) {
 	case *DGRound1Message:
-		p.temp.dgRound1Messages[fromPIdx] = msg
+		store = p.temp.dgRound1Messages
 	case *DGRound2Message1:
-		p.temp.dgRound2Message1s[fromPIdx] = msg
+		store = p.temp.dgRound2Message1s
 	case *DGRound2Message2:
-		p.temp.dgRound2Message2s[fromPIdx] = msg
+		store = p.temp.dgRound2Message2s
 	case *DGRound3Message1:
-		p.temp.dgRound3Message1s[fromPIdx] = msg
+		store = p.temp.dgRound3Message1s
 	case *DGRound3Message2:
-		p.temp.dgRound3Message2s[fromPIdx] = msg
+		store = p.temp.dgRound3Message2s
 	case *DGRound4Message1:
-		p.temp.dgRound4Message1s[fromPIdx] = msg
+		store = p.temp.dgRound4Message1s
 	case *DGRound4Message2:
-		p.temp.dgRound4Message2s[fromPIdx] = msg
+		store = p.temp.dgRound4Message2s
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warningf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
-	return true, nil
+	return p.StoreMessageOnce(store, msg)
 }
 
 func (p *LocalParty) PartyID() *tss.PartyID {
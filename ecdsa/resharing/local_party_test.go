@@ -8,6 +8,8 @@ package resharing_test
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"math/big"
 	"runtime"
@@ -19,6 +21,7 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
 	. "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
 	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
@@ -37,16 +40,52 @@ func setUp(level string) {
 	}
 }
 
+func TestEncryptedShares(t *testing.T) {
+	session := test.NewSession(tss.S256(), tss.GenerateTestPartyIDs(3), 1,
+		func(_ int, params *tss.Parameters) { params.SetEncryptShares() })
+	assert.NoError(t, session.SetIdentityKeys())
+	pIDs := session.PartyIDs
+
+	share := common.GetRandomPositiveInt(rand.Reader, tss.S256().Params().N)
+	msg, err := NewEncryptedDGRound3Message1(session.Params[0], pIDs[1], &vss.Share{Share: share})
+	assert.NoError(t, err)
+	bz, routing, err := msg.WireBytes()
+	assert.NoError(t, err)
+	parsed, err := tss.ParseWireMessageWithRouting(bz, routing)
+	assert.NoError(t, err)
+	r3msg1 := parsed.Content().(*DGRound3Message1)
+	assert.True(t, r3msg1.ValidateBasic())
+	assert.Empty(t, r3msg1.GetShare())
+
+	// only the new committee member it was sent to can open the share
+	opened, err := r3msg1.OpenShare(session.Params[1], pIDs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, share, opened)
+	_, err = r3msg1.OpenShare(session.Params[2], pIDs[0])
+	assert.Error(t, err)
+
+	// a share in the clear is refused
+	plain := NewDGRound3Message1(pIDs[1], pIDs[0], &vss.Share{Share: share})
+	_, err = plain.Content().(*DGRound3Message1).OpenShare(session.Params[1], pIDs[0])
+	assert.Error(t, err)
+}
+
 func TestE2EConcurrent(t *testing.T) {
 	setUp("info")
+	testE2EConcurrent(t, tss.S256())
+}
 
-	// tss.SetCurve(elliptic.P256())
+func TestE2EConcurrentP256(t *testing.T) {
+	setUp("info")
+	testE2EConcurrent(t, tss.P256())
+}
 
+func testE2EConcurrent(t *testing.T, ec elliptic.Curve) {
 	threshold, newThreshold := testThreshold, testThreshold
 
 	// PHASE: load keygen fixtures
 	firstPartyIdx, extraParties := 1, 1 // extra can be 0 to N-first
-	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold+1+extraParties+firstPartyIdx, firstPartyIdx)
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixturesForCurve(ec, testThreshold+1+extraParties+firstPartyIdx, firstPartyIdx)
 	assert.NoError(t, err, "should load keygen fixtures")
 
 	// PHASE: resharing
@@ -72,13 +111,13 @@ func TestE2EConcurrent(t *testing.T) {
 
 	// init the old parties first
 	for j, pID := range oldPIDs {
-		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		params := tss.NewReSharingParameters(ec, oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
 		P := NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty) // discard old key data
 		oldCommittee = append(oldCommittee, P)
 	}
 	// init the new parties
 	for j, pID := range newPIDs {
-		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		params := tss.NewReSharingParameters(ec, oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
 		// do not use in untrusted setting
 		params.SetNoProofMod()
 		// do not use in untrusted setting
@@ -154,7 +193,7 @@ func TestE2EConcurrent(t *testing.T) {
 				for j, key := range newKeys {
 					// xj test: BigXj == xj*G
 					xj := key.Xi
-					gXj := crypto.ScalarBaseMult(tss.S256(), xj)
+					gXj := crypto.ScalarBaseMult(ec, xj)
 					BigXj := key.BigXj[j]
 					assert.True(t, BigXj.Equals(gXj), "ensure BigX_j == g^x_j")
 				}
@@ -176,7 +215,7 @@ signing:
 	signEndCh := make(chan *common.SignatureData, len(signPIDs))
 
 	for j, signPID := range signPIDs {
-		params := tss.NewParameters(tss.S256(), signP2pCtx, signPID, len(signPIDs), newThreshold)
+		params := tss.NewParameters(ec, signP2pCtx, signPID, len(signPIDs), newThreshold)
 		P := signing.NewLocalParty(big.NewInt(42), params, signKeys[j], signOutCh, signEndCh).(*signing.LocalParty)
 		signParties = append(signParties, P)
 		go func(P *signing.LocalParty) {
@@ -219,7 +258,7 @@ signing:
 				// BEGIN ECDSA verify
 				pkX, pkY := signKeys[0].ECDSAPub.X(), signKeys[0].ECDSAPub.Y()
 				pk := ecdsa.PublicKey{
-					Curve: tss.S256(),
+					Curve: ec,
 					X:     pkX,
 					Y:     pkY,
 				}
@@ -236,3 +275,107 @@ signing:
 		}
 	}
 }
+
+func TestE2ESnapshotAndResume(t *testing.T) {
+	setUp("info")
+
+	threshold, newThreshold := testThreshold, testThreshold
+
+	// PHASE: load keygen fixtures
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: resharing
+	// messages are delivered one at a time so that new party 0 can be restarted between two of them
+	oldP2PCtx := tss.NewPeerContext(oldPIDs)
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	newP2PCtx := tss.NewPeerContext(newPIDs)
+	newPCount := len(newPIDs)
+
+	errCh := make(chan *tss.Error, len(oldPIDs)+newPCount)
+	outCh := make(chan tss.Message, (len(oldPIDs)+newPCount)*(len(oldPIDs)+newPCount)*10)
+	endCh := make(chan *keygen.LocalPartySaveData, len(oldPIDs)+newPCount)
+
+	updater := test.SharedPartyUpdater
+
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty))
+	}
+	newCommittee := make([]*LocalParty, 0, newPCount)
+	newParams := make([]*tss.ReSharingParameters, 0, newPCount)
+	newSaves := make([]keygen.LocalPartySaveData, 0, newPCount)
+	for j, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		// do not use in untrusted setting
+		params.SetNoProofMod()
+		// do not use in untrusted setting
+		params.SetNoProofFac()
+		save := keygen.NewLocalPartySaveData(newPCount)
+		save.LocalPreParams = fixtures[j].LocalPreParams
+		newParams, newSaves = append(newParams, params), append(newSaves, save)
+		newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range append(newCommittee, oldCommittee...) {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	restarted := false
+	// restart new party 0 once it has stored its first round 3 message
+	restart := func(msg tss.Message) {
+		if _, ok := msg.(tss.ParsedMessage).Content().(*DGRound3Message1); restarted || !ok {
+			return
+		}
+		snapshot, err := newCommittee[0].Snapshot()
+		assert.NoError(t, err, "should snapshot the party")
+		P, err := RestoreLocalParty(snapshot, newParams[0], newSaves[0], outCh, endCh)
+		assert.NoError(t, err, "should restore the party")
+		newCommittee[0], restarted = P, true
+		if err := P.Resume(); err != nil {
+			errCh <- err
+		}
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, newPCount)
+	for ended := 0; ended < len(oldCommittee)+len(newCommittee); {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					updater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					updater(newCommittee[destP.Index], msg, errCh)
+					if destP.Index == 0 {
+						restart(msg)
+					}
+				}
+			}
+
+		case save := <-endCh:
+			if save.Xi != nil {
+				index, err := save.OriginalIndex()
+				assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+				newKeys[index] = *save
+			}
+			ended++
+		}
+	}
+	assert.True(t, restarted, "new party 0 should have been restarted")
+	for j, key := range newKeys {
+		assert.True(t, key.ECDSAPub.Equals(oldKeys[0].ECDSAPub), "the public key should not change")
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.S256(), key.Xi)), "ensure BigX_j == g^x_j")
+	}
+}
@@ -8,8 +8,11 @@ package resharing
 
 import (
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
@@ -18,6 +21,7 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/crypto/modproof"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -62,10 +66,14 @@ func NewDGRound1Message(
 }
 
 func (m *DGRound1Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound1Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.EcdsaPubX) &&
-		common.NonEmptyBytes(m.EcdsaPubY) &&
-		common.NonEmptyBytes(m.VCommitment)
+		common.NonEmptyBytesMaxBits(m.EcdsaPubX, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.EcdsaPubY, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.VCommitment, keygen.MaxCommitmentBits())
 }
 
 func (m *DGRound1Message) UnmarshalECDSAPub(ec elliptic.Curve) (*crypto.ECPoint, error) {
@@ -125,13 +133,15 @@ func (m *DGRound2Message1) ValidateBasic() bool {
 	return m != nil &&
 		// use with NoProofFac()
 		// common.NonEmptyMultiBytes(m.ModProof, modproof.ProofModBytesParts) &&
-		common.NonEmptyBytes(m.PaillierN) &&
-		common.NonEmptyBytes(m.NTilde) &&
-		common.NonEmptyBytes(m.H1) &&
-		common.NonEmptyBytes(m.H2) &&
+		len(m.ModProof) <= modproof.ProofModBytesParts &&
+		common.MultiBytesMaxBits(m.ModProof, keygen.MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.PaillierN, keygen.MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.NTilde, keygen.MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.H1, keygen.MaxPaillierBits) &&
+		common.NonEmptyBytesMaxBits(m.H2, keygen.MaxPaillierBits) &&
 		// expected len of dln proof = sizeof(int64) + len(alpha) + len(t)
-		common.NonEmptyMultiBytes(m.GetDlnproof_1(), 2+(dlnproof.Iterations*2)) &&
-		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2))
+		common.NonEmptyMultiBytesMaxBits(m.GetDlnproof_1(), keygen.MaxPaillierBits, 2+(dlnproof.Iterations*2)) &&
+		common.NonEmptyMultiBytesMaxBits(m.GetDlnproof_2(), keygen.MaxPaillierBits, 2+(dlnproof.Iterations*2))
 }
 
 func (m *DGRound2Message1) UnmarshalPaillierPK() *paillier.PublicKey {
@@ -205,9 +215,48 @@ func NewDGRound3Message1(
 	return tss.NewMessage(meta, content, msg)
 }
 
+// NewEncryptedDGRound3Message1 is NewDGRound3Message1 with the share encrypted to the identity key of `to`,
+// for parties that were given tss.Parameters.SetEncryptShares
+func NewEncryptedDGRound3Message1(
+	params *tss.Parameters,
+	to *tss.PartyID,
+	share *vss.Share,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:             params.PartyID(),
+		To:               []*tss.PartyID{to},
+		IsBroadcast:      false,
+		IsToOldCommittee: false,
+	}
+	content := &DGRound3Message1{}
+	encShare, err := params.SealShare(to, string(proto.MessageName(content)), share.Share.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	content.EncryptedShare = encShare
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
 func (m *DGRound3Message1) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound3Message1) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.Share)
+		keygen.ValidShareBytes(m.GetShare(), m.GetEncryptedShare(), ec)
+}
+
+// OpenShare returns the share sent by `from`, decrypting it when it was encrypted to this party
+func (m *DGRound3Message1) OpenShare(params *tss.Parameters, from *tss.PartyID) (*big.Int, error) {
+	share, err := params.OpenShare(from, string(proto.MessageName(m)), m.GetShare(), m.GetEncryptedShare())
+	if err != nil {
+		return nil, err
+	}
+	if !common.NonEmptyBytesMaxBits(share, keygen.MaxScalarBits(params.EC())) {
+		return nil, errors.New("received an invalid share")
+	}
+	return new(big.Int).SetBytes(share), nil
 }
 
 // ----- //
@@ -232,8 +281,12 @@ func NewDGRound3Message2(
 }
 
 func (m *DGRound3Message2) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound3Message2) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.VDecommitment)
+		common.NonEmptyMultiBytesMaxBits(m.VDecommitment, keygen.MaxDeCommitmentBits(ec))
 }
 
 func (m *DGRound3Message2) UnmarshalVDeCommitment() cmt.HashDeCommitment {
@@ -282,7 +335,13 @@ func NewDGRound4Message1(
 }
 
 func (m *DGRound4Message1) ValidateBasic() bool {
-	return m != nil
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound4Message1) ValidateCurve(ec elliptic.Curve) bool {
+	return m != nil &&
+		len(m.GetFacProof()) <= facproof.ProofFacBytesParts &&
+		common.MultiBytesMaxBits(m.GetFacProof(), keygen.MaxFacProofBits(ec))
 	// use with NoProofFac()
 	// && common.NonEmptyMultiBytes(m.GetFacProof(), facproof.ProofFacBytesParts)
 }
@@ -9,7 +9,6 @@ package resharing
 import (
 	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
@@ -40,7 +39,7 @@ func (round *round1) Start() *tss.Error {
 	}
 	round.allOldOK()
 
-	round.temp.ssidNonce = new(big.Int).SetUint64(uint64(0))
+	round.temp.ssidNonce = round.SSIDNonce()
 	ssid, err := round.getSSID()
 	if err != nil {
 		return round.WrapError(err)
@@ -79,6 +78,9 @@ func (round *round1) Start() *tss.Error {
 		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
 		round.input.ECDSAPub, vCmt.C, ssid)
 	round.temp.dgRound1Messages[i] = r1msg
+	if err := round.StampMessage(r1msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r1msg
 
 	return nil
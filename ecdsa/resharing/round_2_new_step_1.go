@@ -8,6 +8,8 @@ package resharing
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"errors"
 	"math/big"
 
@@ -55,6 +57,9 @@ func (round *round2) Start() *tss.Error {
 	r2msg1 := NewDGRound2Message2(
 		round.OldParties().IDs().Exclude(round.PartyID()), round.PartyID())
 	round.temp.dgRound2Message2s[i] = r2msg1
+	if err := round.StampMessage(r2msg1); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r2msg1
 
 	// 1.
@@ -69,8 +74,10 @@ func (round *round2) Start() *tss.Error {
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
 	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), round.SafePrimeGenTimeout())
+		defer cancel()
 		var err error
-		preParams, err = keygen.GeneratePreParams(round.SafePrimeGenTimeout(), round.Concurrency())
+		preParams, err = keygen.GeneratePreParamsWithLogger(ctx, rand.Reader, round.Logger(), round.Concurrency())
 		if err != nil {
 			return round.WrapError(errors.New("pre-params generation failed"), Pi)
 		}
@@ -106,6 +113,9 @@ func (round *round2) Start() *tss.Error {
 		return round.WrapError(err, Pi)
 	}
 	round.temp.dgRound2Message1s[i] = r2msg2
+	if err := round.StampMessage(r2msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r2msg2
 
 	// for this P: SAVE de-commitments, paillier keys for round 2
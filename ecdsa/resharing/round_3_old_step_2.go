@@ -33,7 +33,16 @@ func (round *round3) Start() *tss.Error {
 	for j, Pj := range round.NewParties().IDs() {
 		share := round.temp.NewShares[j]
 		r3msg1 := NewDGRound3Message1(Pj, round.PartyID(), share)
+		if round.EncryptShares() {
+			var err error
+			if r3msg1, err = NewEncryptedDGRound3Message1(round.Params(), Pj, share); err != nil {
+				return round.WrapError(err)
+			}
+		}
 		round.temp.dgRound3Message1s[i] = r3msg1
+		if err := round.StampMessage(r3msg1); err != nil {
+			return round.WrapError(err)
+		}
 		round.out <- r3msg1
 	}
 
@@ -42,6 +51,9 @@ func (round *round3) Start() *tss.Error {
 		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
 		vDeCmt)
 	round.temp.dgRound3Message2s[i] = r3msg2
+	if err := round.StampMessage(r3msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r3msg2
 
 	return nil
@@ -39,7 +39,7 @@ func (round *round4) Start() *tss.Error {
 		return nil
 	}
 
-	common.Logger.Debugf(
+	round.Logger().Debugf(
 		"%s Setting up DLN verification with concurrency level of %d",
 		round.PartyID(),
 		round.Concurrency(),
@@ -81,36 +81,45 @@ func (round *round4) Start() *tss.Error {
 				if !round.Parameters.NoProofMod() {
 					paiProofCulprits[j] = msg.GetFrom()
 				}
-				common.Logger.Warningf("modProof verify failed for party %s", msg.GetFrom(), err)
+				round.Logger().Warningf("modProof verify failed for party %s", msg.GetFrom(), err)
 				return
 			}
 			ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
-			if ok := modProof.Verify(ContextJ, paiPK.N); !ok {
+			ok := modProof.Verify(ContextJ, paiPK.N)
+			round.observeProof(msg.GetFrom(), tss.ProofMod, ok)
+			if !ok {
 				paiProofCulprits[j] = msg.GetFrom()
-				common.Logger.Warningf("modProof verify failed for party %s", msg.GetFrom(), err)
+				round.Logger().Warningf("modProof verify failed for party %s", msg.GetFrom(), err)
 			}
 		}(j, msg, r2msg1)
 		_j := j
 		_msg := msg
 		dlnVerifier.VerifyDLNProof1(r2msg1, H1j, H2j, NTildej, func(isValid bool) {
+			round.observeProof(_msg.GetFrom(), tss.ProofDLN, isValid)
 			if !isValid {
 				dlnProof1FailCulprits[_j] = _msg.GetFrom()
-				common.Logger.Warningf("dln proof 1 verify failed for party %s", _msg.GetFrom())
+				round.Logger().Warningf("dln proof 1 verify failed for party %s", _msg.GetFrom())
 			}
 			wg.Done()
 		})
 		dlnVerifier.VerifyDLNProof2(r2msg1, H2j, H1j, NTildej, func(isValid bool) {
+			round.observeProof(_msg.GetFrom(), tss.ProofDLN, isValid)
 			if !isValid {
 				dlnProof2FailCulprits[_j] = _msg.GetFrom()
-				common.Logger.Warningf("dln proof 2 verify failed for party %s", _msg.GetFrom())
+				round.Logger().Warningf("dln proof 2 verify failed for party %s", _msg.GetFrom())
 			}
 			wg.Done()
 		})
 	}
 	wg.Wait()
-	for _, culprit := range append(append(paiProofCulprits, dlnProof1FailCulprits...), dlnProof2FailCulprits...) {
+	for _, culprit := range paiProofCulprits {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+			return round.WrapError(tss.NewProofError(tss.ProofMod, errors.New("mod proof verification failed")), culprit)
+		}
+	}
+	for _, culprit := range append(dlnProof1FailCulprits, dlnProof2FailCulprits...) {
+		if culprit != nil {
+			return round.WrapError(tss.NewProofError(tss.ProofDLN, errors.New("dln proof verification failed")), culprit)
 		}
 	}
 	// save NTilde_j, h1_j, h2_j received in NewCommitteeStep1 here
@@ -152,14 +161,20 @@ func (round *round4) Start() *tss.Error {
 
 		// 8.
 		r3msg1 := round.temp.dgRound3Message1s[j].Content().(*DGRound3Message1)
+		share, err := r3msg1.OpenShare(round.Params(), round.Parties().IDs()[j])
+		if err != nil {
+			return round.WrapError(err, round.Parties().IDs()[j])
+		}
 		sharej := &vss.Share{
 			Threshold: round.NewThreshold(),
 			ID:        round.PartyID().KeyInt(),
-			Share:     new(big.Int).SetBytes(r3msg1.Share),
+			Share:     share,
 		}
-		if ok := sharej.Verify(round.Params().EC(), round.NewThreshold(), vj); !ok {
+		ok = sharej.Verify(round.Params().EC(), round.NewThreshold(), vj)
+		round.observeProof(round.Parties().IDs()[j], tss.ProofVSS, ok)
+		if !ok {
 			// TODO collect culprits and return a list of them as per convention
-			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
+			return round.WrapError(tss.NewProofError(tss.ProofVSS, errors.New("share from old committee did not pass Verify()")), round.Parties().IDs()[j])
 		}
 
 		// 9.
@@ -229,12 +244,18 @@ func (round *round4) Start() *tss.Error {
 			}
 		}
 		r4msg1 := NewDGRound4Message1(Pj, Pi, facProof)
+		if err := round.StampMessage(r4msg1); err != nil {
+			return round.WrapError(err)
+		}
 		round.out <- r4msg1
 	}
 
 	// Send an "ACK" message to both committees to signal that we're ready to save our data
 	r4msg2 := NewDGRound4Message2(round.OldAndNewParties(), Pi)
 	round.temp.dgRound4Message2s[i] = r4msg2
+	if err := round.StampMessage(r4msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r4msg2
 
 	return nil
@@ -10,7 +10,6 @@ import (
 	"errors"
 	"math/big"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -51,16 +50,18 @@ func (round *round5) Start() *tss.Error {
 			r4msg1 := msg.Content().(*DGRound4Message1)
 			proof, err := r4msg1.UnmarshalFacProof()
 			if err != nil && round.Parameters.NoProofFac() {
-				common.Logger.Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
+				round.Logger().Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
 			} else {
 				if err != nil {
-					common.Logger.Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
+					round.Logger().Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
 					return round.WrapError(err, round.NewParties().IDs()[j])
 				}
-				if ok := proof.Verify(ContextI, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
-					round.save.H1i, round.save.H2i); !ok {
-					common.Logger.Warningf("facProof verify failed for party %s", msg.GetFrom(), err)
-					return round.WrapError(err, round.NewParties().IDs()[j])
+				ok := proof.Verify(ContextI, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+					round.save.H1i, round.save.H2i)
+				round.observeProof(msg.GetFrom(), tss.ProofFac, ok)
+				if !ok {
+					round.Logger().Warningf("facProof verify failed for party %s", msg.GetFrom())
+					return round.WrapError(tss.NewProofError(tss.ProofFac, errors.New("facProof verify failed")), round.NewParties().IDs()[j])
 				}
 			}
 
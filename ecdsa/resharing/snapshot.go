@@ -0,0 +1,155 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const lastRound = 5
+
+type (
+	// partySnapshot is the JSON form of a resharing party that is waiting for the messages of a round
+	partySnapshot struct {
+		Round        int
+		OldOK, NewOK []bool
+
+		Messages [][]*tss.SnapshotMessage
+		Save     keygen.LocalPartySaveData
+
+		NewShares vss.Shares
+		VD        cmt.HashDeCommitment
+		NewXi     *big.Int
+		NewKs     []*big.Int
+		NewBigXjs []*crypto.ECPoint
+
+		SSIDNonce *big.Int
+		SSID      []byte
+	}
+
+	// every round embeds base, which holds the state that is shared by the rounds
+	baseRound interface {
+		roundBase() *base
+	}
+)
+
+func (store *localMessageStore) all() []*[]tss.ParsedMessage {
+	return []*[]tss.ParsedMessage{
+		&store.dgRound1Messages,
+		&store.dgRound2Message1s,
+		&store.dgRound2Message2s,
+		&store.dgRound3Message1s,
+		&store.dgRound3Message2s,
+		&store.dgRound4Message1s,
+		&store.dgRound4Message2s,
+	}
+}
+
+// Snapshot serialises the state of a running party: its round, its temp data, the new key data it has built so far
+// and the messages it has stored. A party rebuilt from it with RestoreLocalParty continues from the same round after a restart.
+// The snapshot contains the new secret shares and Paillier key and must be kept as secret as the key.
+func (p *LocalParty) Snapshot() ([]byte, error) {
+	return tss.BaseSnapshot(p, func(round tss.Round) ([]byte, error) {
+		b, t := round.(baseRound).roundBase(), &p.temp
+		snap := &partySnapshot{
+			Round: round.RoundNumber(),
+			OldOK: append([]bool{}, b.oldOK...),
+			NewOK: append([]bool{}, b.newOK...),
+			Save:  p.save,
+
+			NewShares: t.NewShares,
+			VD:        t.VD,
+			NewXi:     t.newXi,
+			NewKs:     t.newKs,
+			NewBigXjs: t.newBigXjs,
+
+			SSIDNonce: t.ssidNonce,
+			SSID:      t.ssid,
+		}
+		for _, store := range t.all() {
+			msgs, err := tss.SnapshotMessages(*store)
+			if err != nil {
+				return nil, err
+			}
+			snap.Messages = append(snap.Messages, msgs)
+		}
+		return json.Marshal(snap)
+	})
+}
+
+// RestoreLocalParty rebuilds a party from a Snapshot, using the same parameters and key that the party was created with.
+// Call Resume rather than Start on the returned party; it will process the stored messages and carry on from there.
+func RestoreLocalParty(
+	snapshot []byte,
+	params *tss.ReSharingParameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) (*LocalParty, error) {
+	snap := new(partySnapshot)
+	if err := json.Unmarshal(snapshot, snap); err != nil {
+		return nil, err
+	}
+	if snap.Round < 1 || lastRound < snap.Round {
+		return nil, fmt.Errorf("snapshot has an invalid round number %d", snap.Round)
+	}
+	if len(snap.OldOK) != len(params.OldParties().IDs()) || len(snap.NewOK) != params.NewPartyCount() {
+		return nil, errors.New("snapshot does not match the committees of the parameters")
+	}
+	p := NewLocalParty(params, key, out, end).(*LocalParty)
+	t := &p.temp
+	stores := t.all()
+	if len(snap.Messages) != len(stores) {
+		return nil, errors.New("snapshot has an unexpected number of message stores")
+	}
+	for i, store := range stores {
+		if err := tss.RestoreMessages(*store, snap.Messages[i]); err != nil {
+			return nil, err
+		}
+	}
+	p.save = snap.Save
+	t.NewShares, t.VD = snap.NewShares, snap.VD
+	t.newXi, t.newKs, t.newBigXjs = snap.NewXi, snap.NewKs, snap.NewBigXjs
+	t.ssidNonce, t.ssid = snap.SSIDNonce, snap.SSID
+
+	round := p.FirstRound()
+	for n := 1; n < snap.Round; n++ {
+		round = round.NextRound()
+	}
+	b := round.(baseRound).roundBase()
+	b.number, b.started = snap.Round, true
+	copy(b.oldOK, snap.OldOK)
+	copy(b.newOK, snap.NewOK)
+	p.resumeRound = round
+	return p, nil
+}
+
+func (p *LocalParty) Resume() *tss.Error {
+	return p.ResumeWithContext(context.Background())
+}
+
+// ResumeWithContext resumes a party returned by RestoreLocalParty; see StartWithContext for the meaning of ctx
+func (p *LocalParty) ResumeWithContext(ctx context.Context) *tss.Error {
+	if p.resumeRound == nil {
+		return p.WrapError(errors.New("could not resume. this party was not restored from a snapshot"))
+	}
+	return tss.BaseResumeWithContext(ctx, p, p.resumeRound, TaskName)
+}
+
+func (round *base) roundBase() *base {
+	return round
+}
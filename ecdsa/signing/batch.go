@@ -0,0 +1,431 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"context"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	BatchTaskName = "signing-batch"
+)
+
+// Implements Party
+// Implements Stringer
+var (
+	_ tss.Party    = (*BatchLocalParty)(nil)
+	_ fmt.Stringer = (*BatchLocalParty)(nil)
+)
+
+type (
+	// BatchLocalParty signs many messages in one session. It runs a signing of each message side by side and sends the
+	// messages of a round of all of them in one SignBatchMessage, so that the session takes as many rounds as the signing
+	// of a single message, whatever the size of the batch.
+	BatchLocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		key                 keygen.LocalPartySaveData
+		msgs                []*big.Int
+		keyDerivationDeltas []*big.Int
+		fullBytesLen        int
+		temp                batchTempData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- []*common.SignatureData
+	}
+
+	batchTempData struct {
+		// the signing of each message of the batch and the channels that it outputs to
+		parties []*LocalParty
+		outs    []chan tss.Message
+		ends    []chan *common.SignatureData
+
+		// batch messages that were stored but not yet passed on to the signings
+		received []tss.ParsedMessage
+	}
+
+	batchBase struct {
+		*tss.Parameters
+		temp    *batchTempData
+		out     chan<- tss.Message
+		end     chan<- []*common.SignatureData
+		started bool
+		number  int
+	}
+	// batchRound stands for each round of the signings in turn; it proceeds when they have all started their next round
+	batchRound struct {
+		*batchBase
+	}
+	batchFinalization struct {
+		*batchRound
+	}
+)
+
+var (
+	_ tss.Round = (*batchRound)(nil)
+	_ tss.Round = (*batchFinalization)(nil)
+)
+
+// NewBatchLocalParty returns a party that signs each of `msgs` with the key. `keyDerivationDeltas` is either nil or
+// holds a key derivation delta for each message, nil for one that is signed with the key itself; see NewLocalPartyWithKDD.
+// The signatures are sent to `end` in the order of `msgs`.
+func NewBatchLocalParty(
+	msgs []*big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDeltas []*big.Int,
+	out chan<- tss.Message,
+	end chan<- []*common.SignatureData,
+	fullBytesLen ...int,
+) tss.Party {
+	p := &BatchLocalParty{
+		BaseParty:           new(tss.BaseParty),
+		params:              params,
+		key:                 key,
+		msgs:                msgs,
+		keyDerivationDeltas: keyDerivationDeltas,
+		temp:                batchTempData{},
+		out:                 out,
+		end:                 end,
+	}
+	if len(fullBytesLen) > 0 {
+		p.fullBytesLen = fullBytesLen[0]
+	}
+	return p
+}
+
+func (p *BatchLocalParty) FirstRound() tss.Round {
+	return &batchRound{&batchBase{p.params, &p.temp, p.out, p.end, false, 0}}
+}
+
+func (p *BatchLocalParty) Start() *tss.Error {
+	return p.StartWithContext(context.Background())
+}
+
+func (p *BatchLocalParty) StartWithContext(ctx context.Context) *tss.Error {
+	return tss.BaseStartWithContext(ctx, p, BatchTaskName, func(round tss.Round) *tss.Error {
+		if err := p.prepare(); err != nil {
+			return round.WrapError(err)
+		}
+		return nil
+	})
+}
+
+func (p *BatchLocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, BatchTaskName)
+}
+
+func (p *BatchLocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *BatchLocalParty) UpdateFromBytesWithRouting(wireBytes []byte, routing *tss.MessageRouting) (bool, *tss.Error) {
+	return tss.UpdateFromBytesWithRouting(p, wireBytes, routing)
+}
+
+func (p *BatchLocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message was signed by the identity key of its sender
+	if err := p.params.VerifyMessage(msg); err != nil {
+		return false, p.WrapError(err)
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+	}
+	return true, nil
+}
+
+func (p *BatchLocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	batch, ok := msg.Content().(*SignBatchMessage)
+	if !ok {
+		p.params.Logger().Warningf("unrecognised message ignored: %v", msg)
+		return false, nil
+	}
+	if len(batch.GetMessages()) != len(p.msgs) {
+		return false, p.WrapError(fmt.Errorf("%w: received a batch of %d messages, expected %d",
+			tss.ErrInvalidMessage, len(batch.GetMessages()), len(p.msgs)), msg.GetFrom())
+	}
+	// the signings store the messages they carry, and detect retransmissions and equivocation
+	p.temp.received = append(p.temp.received, msg)
+	return true, nil
+}
+
+func (p *BatchLocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *BatchLocalParty) String() string {
+	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
+}
+
+// prepare creates the signing of each message. The signings are not signed with the identity key, as the batch
+// messages that carry them are, and each is given a session id of its own so that their proofs cannot be swapped.
+func (p *BatchLocalParty) prepare() error {
+	if len(p.msgs) == 0 {
+		return errors.New("the batch has no message to sign")
+	}
+	if p.keyDerivationDeltas != nil && len(p.keyDerivationDeltas) != len(p.msgs) {
+		return fmt.Errorf("the batch has %d messages but %d key derivation deltas", len(p.msgs), len(p.keyDerivationDeltas))
+	}
+	partyCount := len(p.params.Parties().IDs())
+	for i, msg := range p.msgs {
+		key := p.key
+		var keyDerivationDelta *big.Int
+		if p.keyDerivationDeltas != nil && p.keyDerivationDeltas[i] != nil {
+			keyDerivationDelta = p.keyDerivationDeltas[i]
+			var err error
+			if key, err = deriveKey(p.params.EC(), p.key, keyDerivationDelta); err != nil {
+				return fmt.Errorf("message %d of the batch: %w", i, err)
+			}
+		}
+		params := *p.params
+		params.SetSessionID(common.SHA512_256(p.params.SessionID(), big.NewInt(int64(i)).Bytes()))
+		params.SetSigner(nil)
+		params.SetPeerKeys(nil)
+		params.SetRoundTimeout(0)
+		out := make(chan tss.Message, 2*partyCount)
+		end := make(chan *common.SignatureData, 1)
+		P := NewLocalPartyWithKDD(msg, &params, key, keyDerivationDelta, out, end, p.fullBytesLen).(*LocalParty)
+		p.temp.parties = append(p.temp.parties, P)
+		p.temp.outs = append(p.temp.outs, out)
+		p.temp.ends = append(p.temp.ends, end)
+	}
+	return nil
+}
+
+// deriveKey returns a copy of `key` for the child key of `keyDerivationDelta`, like UpdatePublicKeyAndAdjustBigXj
+func deriveKey(ec elliptic.Curve, key keygen.LocalPartySaveData, keyDerivationDelta *big.Int) (keygen.LocalPartySaveData, error) {
+	childPk, err := key.ECDSAPub.Add(crypto.ScalarBaseMult(ec, keyDerivationDelta))
+	if err != nil {
+		return key, err
+	}
+	key.BigXj = append([]*crypto.ECPoint{}, key.BigXj...)
+	keys := []keygen.LocalPartySaveData{key}
+	if err = UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta, keys, childPk.ToECDSAPubKey(), ec); err != nil {
+		return key, err
+	}
+	return keys[0], nil
+}
+
+// ----- //
+
+func (round *batchBase) Params() *tss.Parameters {
+	return round.Parameters
+}
+
+func (round *batchBase) RoundNumber() int {
+	return round.number
+}
+
+// WaitingFor is called by a Party for reporting back to the caller
+func (round *batchBase) WaitingFor() []*tss.PartyID {
+	// the signings are in step, so they all wait for the same parties
+	if len(round.temp.parties) == 0 {
+		return nil
+	}
+	return round.temp.parties[0].WaitingFor()
+}
+
+func (round *batchBase) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, BatchTaskName, round.number, round.PartyID(), culprits...)
+}
+
+// signingError wraps the error of the signing of message i of the batch
+func (round *batchBase) signingError(i int, err *tss.Error) *tss.Error {
+	return round.WrapError(fmt.Errorf("message %d of the batch: %w", i, err), err.Culprits()...)
+}
+
+// finished reports whether every signing has output its signature
+func (round *batchBase) finished() bool {
+	for _, P := range round.temp.parties {
+		if P.Running() {
+			return false
+		}
+	}
+	return true
+}
+
+// send batches the messages that the signings output since it was last called, one SignBatchMessage for each type of
+// message and its recipients. A signing may send its messages in any order, but each must send the same messages.
+func (round *batchBase) send() *tss.Error {
+	outs := round.temp.outs
+	// the messages of each signing by their type and recipients, kept in the order that the first signing sent them
+	var recipients []string
+	groups := make(map[string][]tss.Message)
+	for i, ch := range outs {
+		for n := len(ch); 0 < n; n-- {
+			msg := <-ch
+			key := msg.Type() + " to " + recipientsKey(msg)
+			group, ok := groups[key]
+			if !ok && i == 0 {
+				group = make([]tss.Message, len(outs))
+				groups[key] = group
+				recipients = append(recipients, key)
+			} else if !ok || group[i] != nil {
+				return round.WrapError(fmt.Errorf("message %d of the batch was sent to other parties than the others", i))
+			}
+			group[i] = msg
+		}
+	}
+	for _, key := range recipients {
+		group := groups[key]
+		msgs := make([][]byte, len(group))
+		for i, msg := range group {
+			if msg == nil {
+				return round.WrapError(fmt.Errorf("message %d of the batch is out of step with the others", i))
+			}
+			bz, _, err := msg.WireBytes()
+			if err != nil {
+				return round.WrapError(err)
+			}
+			msgs[i] = bz
+		}
+		r := NewSignBatchMessage(group[0].GetTo(), round.PartyID(), group[0].IsBroadcast(), msgs)
+		if err := round.StampMessage(r); err != nil {
+			return round.WrapError(err)
+		}
+		round.out <- r
+	}
+	return nil
+}
+
+// recipientsKey identifies the parties that `msg` is sent to
+func recipientsKey(msg tss.Message) string {
+	if msg.IsBroadcast() {
+		return "broadcast"
+	}
+	idx := make([]int, 0, len(msg.GetTo()))
+	for _, Pj := range msg.GetTo() {
+		idx = append(idx, Pj.Index)
+	}
+	sort.Ints(idx)
+	return fmt.Sprint(idx)
+}
+
+// ----- //
+
+func (round *batchRound) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number++
+	round.started = true
+
+	if round.number == 1 {
+		for i, P := range round.temp.parties {
+			if err := P.Start(); err != nil {
+				return round.signingError(i, err)
+			}
+		}
+	}
+	return round.send()
+}
+
+func (round *batchRound) Update() (bool, *tss.Error) {
+	received := round.temp.received
+	round.temp.received = nil
+	for _, msg := range received {
+		batch := msg.Content().(*SignBatchMessage)
+		for i, P := range round.temp.parties {
+			routing := &tss.MessageRouting{
+				From:        msg.GetFrom(),
+				IsBroadcast: msg.IsBroadcast(),
+				SessionID:   P.params.SessionID(),
+				Version:     round.Version(),
+			}
+			inner, err := tss.ParseWireMessageWithRouting(batch.GetMessages()[i], routing)
+			if err != nil {
+				return false, round.WrapError(fmt.Errorf("%w: message %d of the batch: %v", tss.ErrInvalidMessage, i, err), msg.GetFrom())
+			}
+			if _, err := P.Update(inner); err != nil {
+				return false, round.signingError(i, err)
+			}
+		}
+	}
+	return true, nil
+}
+
+func (round *batchRound) CanAccept(msg tss.ParsedMessage) bool {
+	_, ok := msg.Content().(*SignBatchMessage)
+	return ok
+}
+
+// CanProceed is true once the signings have started their next round, or have all finished
+func (round *batchRound) CanProceed() bool {
+	return round.started && (0 < len(round.temp.outs[0]) || round.finished())
+}
+
+func (round *batchRound) NextRound() tss.Round {
+	round.started = false
+	if round.finished() {
+		return &batchFinalization{round}
+	}
+	return round
+}
+
+// ----- //
+
+func (round *batchFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number++
+	round.started = true
+
+	sigs := make([]*common.SignatureData, len(round.temp.ends))
+	for i, end := range round.temp.ends {
+		select {
+		case sigs[i] = <-end:
+		default:
+			return round.WrapError(fmt.Errorf("message %d of the batch was not signed", i))
+		}
+	}
+	round.end <- sigs
+	return nil
+}
+
+func (round *batchFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *batchFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *batchFinalization) CanProceed() bool {
+	return false
+}
+
+func (round *batchFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -8,11 +8,12 @@ package signing
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"errors"
-	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -36,12 +37,38 @@ func (round *finalization) Start() *tss.Error {
 		sumS = modN.Add(sumS, r9msg.UnmarshalS())
 	}
 
+	if err := buildSignature(round.Params().EC(), round.key.ECDSAPub, round.temp.m, round.temp.fullBytesLen,
+		round.temp.rx, round.temp.ry, sumS, round.data); err != nil {
+		return round.WrapError(err)
+	}
+
+	round.end <- round.data
+
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
+
+// buildSignature saves the signature (rx, sumS) of m in data, with low-S and its recovery id, and verifies it with pub
+func buildSignature(ec elliptic.Curve, pub *crypto.ECPoint, m *big.Int, fullBytesLen int, rx, ry, sumS *big.Int, data *common.SignatureData) error {
 	recid := 0
 	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
-	if round.temp.rx.Cmp(round.Params().EC().Params().N) > 0 {
+	if rx.Cmp(ec.Params().N) > 0 {
 		recid = 2
 	}
-	if round.temp.ry.Bit(0) != 0 {
+	if ry.Bit(0) != 0 {
 		recid |= 1
 	}
 
@@ -49,56 +76,39 @@ func (round *finalization) Start() *tss.Error {
 	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
 	// This is needed because of tendermint checks here:
 	// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
-	secp256k1halfN := new(big.Int).Rsh(round.Params().EC().Params().N, 1)
-	if sumS.Cmp(secp256k1halfN) > 0 {
-		sumS.Sub(round.Params().EC().Params().N, sumS)
+	// low-S is not required by other curves but does no harm, as (r, N-s) is as valid as (r, s)
+	halfN := new(big.Int).Rsh(ec.Params().N, 1)
+	if sumS.Cmp(halfN) > 0 {
+		sumS.Sub(ec.Params().N, sumS)
 		recid ^= 1
 	}
 
 	// save the signature for final output
-	bitSizeInBytes := round.Params().EC().Params().BitSize / 8
-	round.data.R = padToLengthBytesInPlace(round.temp.rx.Bytes(), bitSizeInBytes)
-	round.data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
-	round.data.Signature = append(round.data.R, round.data.S...)
-	round.data.SignatureRecovery = []byte{byte(recid)}
-	if round.temp.fullBytesLen == 0 {
-		round.data.M = round.temp.m.Bytes()
+	bitSizeInBytes := ec.Params().BitSize / 8
+	data.R = padToLengthBytesInPlace(rx.Bytes(), bitSizeInBytes)
+	data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
+	data.Signature = append(data.R, data.S...)
+	data.SignatureRecovery = []byte{byte(recid)}
+	if fullBytesLen == 0 {
+		data.M = m.Bytes()
 	} else {
-		var mBytes = make([]byte, round.temp.fullBytesLen)
-		round.temp.m.FillBytes(mBytes)
-		round.data.M = mBytes
+		var mBytes = make([]byte, fullBytesLen)
+		m.FillBytes(mBytes)
+		data.M = mBytes
 	}
 
 	pk := ecdsa.PublicKey{
-		Curve: round.Params().EC(),
-		X:     round.key.ECDSAPub.X(),
-		Y:     round.key.ECDSAPub.Y(),
+		Curve: ec,
+		X:     pub.X(),
+		Y:     pub.Y(),
 	}
 
-	ok := ecdsa.Verify(&pk, round.data.M, round.temp.rx, sumS)
-	if !ok {
-		return round.WrapError(fmt.Errorf("signature verification failed"))
+	if ok := ecdsa.Verify(&pk, data.M, rx, sumS); !ok {
+		return errors.New("signature verification failed")
 	}
-
-	round.end <- round.data
-
 	return nil
 }
 
-func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
-	// not expecting any incoming messages in this round
-	return false
-}
-
-func (round *finalization) Update() (bool, *tss.Error) {
-	// not expecting any incoming messages in this round
-	return false, nil
-}
-
-func (round *finalization) NextRound() tss.Round {
-	return nil // finished!
-}
-
 func padToLengthBytesInPlace(src []byte, length int) []byte {
 	oriLen := len(src)
 	if oriLen < length {
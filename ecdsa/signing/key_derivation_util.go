@@ -5,6 +5,7 @@ package signing
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
@@ -37,7 +38,12 @@ func UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta *big.Int, keys []keygen.Lo
 	return nil
 }
 
-func derivingPubkeyFromPath(masterPub *crypto.ECPoint, chainCode []byte, path []uint32, ec elliptic.Curve) (*big.Int, *ckd.ExtendedKey, error) {
+// DerivePubkeyFromPath derives the child public key at the non-hardened BIP-32 `path` below `masterPub` and its
+// 32-byte chain code. It returns the key derivation delta to pass to NewLocalPartyWithKDD along with the child key.
+func DerivePubkeyFromPath(masterPub *crypto.ECPoint, chainCode []byte, path []uint32, ec elliptic.Curve) (*big.Int, *ckd.ExtendedKey, error) {
+	if len(chainCode) != 32 {
+		return nil, nil, errors.New("the chain code must be 32 bytes")
+	}
 	// build ecdsa key pair
 	pk := ecdsa.PublicKey{
 		Curve: ec,
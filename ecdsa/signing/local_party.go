@@ -7,6 +7,7 @@
 package signing
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -35,9 +36,16 @@ type (
 		temp localTempData
 		data *common.SignatureData
 
+		// set by RestoreLocalParty to the round that Resume continues from
+		resumeRound tss.Round
+
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *common.SignatureData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		presignEnd chan<- *Presignature
+
+		// set by NewOnlineLocalParty to the presignature that the party signs with from round 5
+		presig *Presignature
 	}
 
 	localMessageStore struct {
@@ -120,11 +128,24 @@ func NewLocalPartyWithKDD(
 	end chan<- *common.SignatureData,
 	fullBytesLen ...int,
 ) tss.Party {
+	return newLocalParty(msg, params, keygen.BuildLocalSaveDataSubset(key, params.Parties().IDs()), keyDerivationDelta, out, end, fullBytesLen...)
+}
+
+// newLocalParty returns a party with `keys`, the save data of the signing parties only
+func newLocalParty(
+	msg *big.Int,
+	params *tss.Parameters,
+	keys keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) *LocalParty {
 	partyCount := len(params.Parties().IDs())
 	p := &LocalParty{
 		BaseParty: new(tss.BaseParty),
 		params:    params,
-		keys:      keygen.BuildLocalSaveDataSubset(key, params.Parties().IDs()),
+		keys:      keys,
 		temp:      localTempData{},
 		data:      &common.SignatureData{},
 		out:       out,
@@ -161,18 +182,30 @@ func NewLocalPartyWithKDD(
 }
 
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	if p.presig != nil {
+		return newOnlineRound5(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	}
+	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end, p.presignEnd)
 }
 
 func (p *LocalParty) Start() *tss.Error {
-	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
-		round1, ok := round.(*round1)
-		if !ok {
+	return p.StartWithContext(context.Background())
+}
+
+func (p *LocalParty) StartWithContext(ctx context.Context) *tss.Error {
+	return tss.BaseStartWithContext(ctx, p, TaskName, func(round tss.Round) *tss.Error {
+		switch round := round.(type) {
+		case *round1:
+			if err := round.prepare(); err != nil {
+				return round.WrapError(err)
+			}
+		case *round5:
+			if err := round.prepareOnline(p.presig); err != nil {
+				return round.WrapError(err)
+			}
+		default:
 			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
 		}
-		if err := round1.prepare(); err != nil {
-			return round.WrapError(err)
-		}
 		return nil
 	})
 }
@@ -189,10 +222,22 @@ func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroa
 	return p.Update(msg)
 }
 
+func (p *LocalParty) UpdateFromBytesWithRouting(wireBytes []byte, routing *tss.MessageRouting) (bool, *tss.Error) {
+	return tss.UpdateFromBytesWithRouting(p, wireBytes, routing)
+}
+
 func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
+	// check that the message was signed by the identity key of its sender
+	if err := p.params.VerifyMessage(msg); err != nil {
+		return false, p.WrapError(err)
+	}
+	// check the integers of the message against the curve of the session
+	if err := p.params.ValidateCurve(msg); err != nil {
+		return false, p.WrapError(err, msg.GetFrom())
+	}
 	// check that the message's "from index" will fit into the array
 	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
@@ -206,36 +251,36 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
-	fromPIdx := msg.GetFrom().Index
-
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// identical replays are ignored and a conflicting message from the same sender is blamed as equivocation.
+	// we still expect the caller to apply spoofing protection.
+	var store []tss.ParsedMessage
 	switch msg.Content().(type) {
 	case *SignRound1Message1:
-		p.temp.signRound1Message1s[fromPIdx] = msg
+		store = p.temp.signRound1Message1s
 	case *SignRound1Message2:
-		p.temp.signRound1Message2s[fromPIdx] = msg
+		store = p.temp.signRound1Message2s
 	case *SignRound2Message:
-		p.temp.signRound2Messages[fromPIdx] = msg
+		store = p.temp.signRound2Messages
 	case *SignRound3Message:
-		p.temp.signRound3Messages[fromPIdx] = msg
+		store = p.temp.signRound3Messages
 	case *SignRound4Message:
-		p.temp.signRound4Messages[fromPIdx] = msg
+		store = p.temp.signRound4Messages
 	case *SignRound5Message:
-		p.temp.signRound5Messages[fromPIdx] = msg
+		store = p.temp.signRound5Messages
 	case *SignRound6Message:
-		p.temp.signRound6Messages[fromPIdx] = msg
+		store = p.temp.signRound6Messages
 	case *SignRound7Message:
-		p.temp.signRound7Messages[fromPIdx] = msg
+		store = p.temp.signRound7Messages
 	case *SignRound8Message:
-		p.temp.signRound8Messages[fromPIdx] = msg
+		store = p.temp.signRound8Messages
 	case *SignRound9Message:
-		p.temp.signRound9Messages[fromPIdx] = msg
+		store = p.temp.signRound9Messages
 	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		p.params.Logger().Warningf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
-	return true, nil
+	return p.StoreMessageOnce(store, msg)
 }
 
 func (p *LocalParty) PartyID() *tss.PartyID {
@@ -9,20 +9,24 @@ package signing
 import (
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
-	"runtime"
-	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/test/simulator"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -37,6 +41,9 @@ func setUp(level string) {
 	}
 }
 
+// messages arrive late, out of order and twice on the network of the end to end tests
+var testNetwork = simulator.Config{Seed: 1, Latency: 10 * time.Millisecond, Jitter: 50 * time.Millisecond, DuplicateRate: 0.2}
+
 func TestE2EConcurrent(t *testing.T) {
 	setUp("info")
 	threshold := testThreshold
@@ -51,84 +58,45 @@ func TestE2EConcurrent(t *testing.T) {
 	// use a shuffled selection of the list of parties for this test
 	p2pCtx := tss.NewPeerContext(signPIDs)
 	parties := make([]*LocalParty, 0, len(signPIDs))
-
-	errCh := make(chan *tss.Error, len(signPIDs))
-	outCh := make(chan tss.Message, len(signPIDs))
 	endCh := make(chan *common.SignatureData, len(signPIDs))
 
-	updater := test.SharedPartyUpdater
-	// init the parties
-	for i := 0; i < len(signPIDs); i++ {
+	stats, err := simulator.Simulate(testNetwork, len(signPIDs), func(i int, out chan<- tss.Message) tss.Party {
 		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
-		P := NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh).(*LocalParty)
+		P := NewLocalParty(big.NewInt(42), params, keys[i], out, endCh).(*LocalParty)
 		parties = append(parties, P)
-		go func(P *LocalParty) {
-			if err := P.Start(); err != nil {
-				errCh <- err
-			}
-		}(P)
+		return P
+	})
+	if !assert.NoError(t, err) {
+		return
 	}
+	assert.Len(t, endCh, len(signPIDs))
+	t.Logf("Done. Delivered %d messages in %s", stats.Delivered, stats.Elapsed)
 
-	var ended int32
-signing:
-	for {
-		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
-		select {
-		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
-			assert.FailNow(t, err.Error())
-			break signing
-
-		case msg := <-outCh:
-			dest := msg.GetTo()
-			if dest == nil {
-				for _, P := range parties {
-					if P.PartyID().Index == msg.GetFrom().Index {
-						continue
-					}
-					go updater(P, msg, errCh)
-				}
-			} else {
-				if dest[0].Index == msg.GetFrom().Index {
-					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
-				}
-				go updater(parties[dest[0].Index], msg, errCh)
-			}
+	R := parties[0].temp.bigR
+	r := parties[0].temp.rx
+	fmt.Printf("sign result: R(%s, %s), r=%s\n", R.X().String(), R.Y().String(), r.String())
 
-		case <-endCh:
-			atomic.AddInt32(&ended, 1)
-			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
-				t.Logf("Done. Received signature data from %d participants", ended)
-				R := parties[0].temp.bigR
-				r := parties[0].temp.rx
-				fmt.Printf("sign result: R(%s, %s), r=%s\n", R.X().String(), R.Y().String(), r.String())
-
-				modN := common.ModInt(tss.S256().Params().N)
-
-				// BEGIN check s correctness
-				sumS := big.NewInt(0)
-				for _, p := range parties {
-					sumS = modN.Add(sumS, p.temp.si)
-				}
-				fmt.Printf("S: %s\n", sumS.String())
-				// END check s correctness
-
-				// BEGIN ECDSA verify
-				pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
-				pk := ecdsa.PublicKey{
-					Curve: tss.EC(),
-					X:     pkX,
-					Y:     pkY,
-				}
-				ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(), R.X(), sumS)
-				assert.True(t, ok, "ecdsa verify must pass")
-				t.Log("ECDSA signing test done.")
-				// END ECDSA verify
+	modN := common.ModInt(tss.S256().Params().N)
 
-				break signing
-			}
-		}
+	// BEGIN check s correctness
+	sumS := big.NewInt(0)
+	for _, p := range parties {
+		sumS = modN.Add(sumS, p.temp.si)
+	}
+	fmt.Printf("S: %s\n", sumS.String())
+	// END check s correctness
+
+	// BEGIN ECDSA verify
+	pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     pkX,
+		Y:     pkY,
 	}
+	ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(), R.X(), sumS)
+	assert.True(t, ok, "ecdsa verify must pass")
+	t.Log("ECDSA signing test done.")
+	// END ECDSA verify
 }
 
 func TestE2EConcurrentWithLeadingZeroInMSG(t *testing.T) {
@@ -145,17 +113,190 @@ func TestE2EConcurrentWithLeadingZeroInMSG(t *testing.T) {
 	// use a shuffled selection of the list of parties for this test
 	p2pCtx := tss.NewPeerContext(signPIDs)
 	parties := make([]*LocalParty, 0, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	msgData, _ := hex.DecodeString("00f163ee51bcaeff9cdff5e0e3c1a646abd19885fffbab0b3b4236e0cf95c9f5")
+	_, err = simulator.Simulate(testNetwork, len(signPIDs), func(i int, out chan<- tss.Message) tss.Party {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P := NewLocalParty(new(big.Int).SetBytes(msgData), params, keys[i], out, endCh, len(msgData)).(*LocalParty)
+		parties = append(parties, P)
+		return P
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, endCh, len(signPIDs))
+
+	R := parties[0].temp.bigR
+	r := parties[0].temp.rx
+	fmt.Printf("sign result: R(%s, %s), r=%s\n", R.X().String(), R.Y().String(), r.String())
+
+	modN := common.ModInt(tss.S256().Params().N)
+
+	// BEGIN check s correctness
+	sumS := big.NewInt(0)
+	for _, p := range parties {
+		sumS = modN.Add(sumS, p.temp.si)
+	}
+	fmt.Printf("S: %s\n", sumS.String())
+	// END check s correctness
+
+	// BEGIN ECDSA verify
+	pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     pkX,
+		Y:     pkY,
+	}
+	ok := ecdsa.Verify(&pk, msgData, R.X(), sumS)
+	assert.True(t, ok, "ecdsa verify must pass")
+	t.Log("ECDSA signing test done.")
+	// END ECDSA verify
+}
+
+func TestE2EWithHDKeyDerivation(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	chainCode := make([]byte, 32)
+	max32b := new(big.Int).Lsh(new(big.Int).SetUint64(1), 256)
+	max32b = new(big.Int).Sub(max32b, new(big.Int).SetUint64(1))
+	fillBytes(common.GetRandomPositiveInt(rand.Reader, max32b), chainCode)
+
+	il, extendedChildPk, errorDerivation := DerivePubkeyFromPath(keys[0].ECDSAPub, chainCode, []uint32{12, 209, 3}, btcec.S256())
+	assert.NoErrorf(t, errorDerivation, "there should not be an error deriving the child public key")
+
+	keyDerivationDelta := il
+
+	err = UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta, keys, &extendedChildPk.PublicKey, btcec.S256())
+	assert.NoErrorf(t, err, "there should not be an error setting the derived keys")
+
+	// PHASE: signing
+	// use a shuffled selection of the list of parties for this test
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	_, err = simulator.Simulate(testNetwork, len(signPIDs), func(i int, out chan<- tss.Message) tss.Party {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P := NewLocalPartyWithKDD(big.NewInt(42), params, keys[i], keyDerivationDelta, out, endCh, 0).(*LocalParty)
+		parties = append(parties, P)
+		return P
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, endCh, len(signPIDs))
+
+	R := parties[0].temp.bigR
+	r := parties[0].temp.rx
+	fmt.Printf("sign result: R(%s, %s), r=%s\n", R.X().String(), R.Y().String(), r.String())
+
+	modN := common.ModInt(tss.S256().Params().N)
+
+	// BEGIN check s correctness
+	sumS := big.NewInt(0)
+	for _, p := range parties {
+		sumS = modN.Add(sumS, p.temp.si)
+	}
+	fmt.Printf("S: %s\n", sumS.String())
+	// END check s correctness
+
+	// BEGIN ECDSA verify
+	pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     pkX,
+		Y:     pkY,
+	}
+	ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(), R.X(), sumS)
+	assert.True(t, ok, "ecdsa verify must pass")
+	t.Log("ECDSA signing test done.")
+	// END ECDSA verify
+}
+
+func TestE2EConcurrentP256WithHDKeyDerivation(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+	ec := tss.P256()
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSetForCurve(ec, testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+	masterPub := keys[0].ECDSAPub.ToECDSAPubKey()
+
+	chainCode := make([]byte, 32)
+	_, err = rand.Read(chainCode)
+	assert.NoError(t, err)
+
+	il, extendedChildPk, errorDerivation := DerivePubkeyFromPath(keys[0].ECDSAPub, chainCode, []uint32{12, 209, 3}, ec)
+	assert.NoErrorf(t, errorDerivation, "there should not be an error deriving the child public key")
+
+	keyDerivationDelta := il
+
+	err = UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta, keys, &extendedChildPk.PublicKey, ec)
+	assert.NoErrorf(t, err, "there should not be an error setting the derived keys")
+	assert.False(t, masterPub.Equal(keys[0].ECDSAPub.ToECDSAPubKey()), "the child key should differ from the master key")
+
+	digest := sha256.Sum256([]byte("threshold P-256 signature"))
+	msg := new(big.Int).SetBytes(digest[:])
+
+	// PHASE: signing
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	_, err = simulator.Simulate(testNetwork, len(signPIDs), func(i int, out chan<- tss.Message) tss.Party {
+		params := tss.NewParameters(ec, p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		return NewLocalPartyWithKDD(msg, params, keys[i], keyDerivationDelta, out, endCh, len(digest))
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, endCh, len(signPIDs))
+	close(endCh)
+	for data := range endCh {
+		assert.Equal(t, digest[:], data.M)
+		r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+		assert.True(t, s.Cmp(new(big.Int).Rsh(ec.Params().N, 1)) <= 0, "s should be normalised to the lower half of the order")
+
+		// the signature must be accepted by the standard library for the derived child key
+		pk := keys[0].ECDSAPub.ToECDSAPubKey()
+		assert.True(t, ecdsa.Verify(pk, digest[:], r, s), "ecdsa verify must pass")
+	}
+}
+
+func TestE2ESnapshotAndResume(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: signing
+	// messages are delivered one at a time so that party 0 can be restarted between two of them
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
 
 	errCh := make(chan *tss.Error, len(signPIDs))
-	outCh := make(chan tss.Message, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs)*10)
 	endCh := make(chan *common.SignatureData, len(signPIDs))
 
 	updater := test.SharedPartyUpdater
-	msgData, _ := hex.DecodeString("00f163ee51bcaeff9cdff5e0e3c1a646abd19885fffbab0b3b4236e0cf95c9f5")
+	paramsOf := make([]*tss.Parameters, 0, len(signPIDs))
 	// init the parties
 	for i := 0; i < len(signPIDs); i++ {
 		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
-		P := NewLocalParty(new(big.Int).SetBytes(msgData), params, keys[i], outCh, endCh, len(msgData)).(*LocalParty)
+		paramsOf = append(paramsOf, params)
+		P := NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh).(*LocalParty)
 		parties = append(parties, P)
 		go func(P *LocalParty) {
 			if err := P.Start(); err != nil {
@@ -164,10 +305,26 @@ func TestE2EConcurrentWithLeadingZeroInMSG(t *testing.T) {
 		}(P)
 	}
 
-	var ended int32
+	restarted := false
+	// restart party 0 once it has stored its first round 4 message
+	restart := func(msg tss.Message) {
+		if _, ok := msg.(tss.ParsedMessage).Content().(*SignRound4Message); restarted || !ok {
+			return
+		}
+		snapshot, err := parties[0].Snapshot()
+		assert.NoError(t, err, "should snapshot the party")
+		P, err := RestoreLocalParty(snapshot, paramsOf[0], keys[0], outCh, endCh)
+		assert.NoError(t, err, "should restore the party")
+		assert.Equal(t, 4, P.resumeRound.RoundNumber())
+		parties[0], restarted = P, true
+		if err := P.Resume(); err != nil {
+			errCh <- err
+		}
+	}
+
+	var sigs []*common.SignatureData
 signing:
 	for {
-		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
 		select {
 		case err := <-errCh:
 			common.Logger.Errorf("Error: %s", err)
@@ -181,157 +338,354 @@ signing:
 					if P.PartyID().Index == msg.GetFrom().Index {
 						continue
 					}
-					go updater(P, msg, errCh)
+					updater(P, msg, errCh)
+					if P.PartyID().Index == 0 {
+						restart(msg)
+					}
 				}
 			} else {
-				if dest[0].Index == msg.GetFrom().Index {
-					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				updater(parties[dest[0].Index], msg, errCh)
+				if dest[0].Index == 0 {
+					restart(msg)
 				}
-				go updater(parties[dest[0].Index], msg, errCh)
 			}
 
-		case <-endCh:
-			atomic.AddInt32(&ended, 1)
-			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
-				t.Logf("Done. Received signature data from %d participants", ended)
-				R := parties[0].temp.bigR
-				r := parties[0].temp.rx
-				fmt.Printf("sign result: R(%s, %s), r=%s\n", R.X().String(), R.Y().String(), r.String())
-
-				modN := common.ModInt(tss.S256().Params().N)
-
-				// BEGIN check s correctness
-				sumS := big.NewInt(0)
-				for _, p := range parties {
-					sumS = modN.Add(sumS, p.temp.si)
-				}
-				fmt.Printf("S: %s\n", sumS.String())
-				// END check s correctness
-
-				// BEGIN ECDSA verify
-				pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
-				pk := ecdsa.PublicKey{
-					Curve: tss.EC(),
-					X:     pkX,
-					Y:     pkY,
-				}
-				ok := ecdsa.Verify(&pk, msgData, R.X(), sumS)
-				assert.True(t, ok, "ecdsa verify must pass")
-				t.Log("ECDSA signing test done.")
-				// END ECDSA verify
-
+		case sig := <-endCh:
+			sigs = append(sigs, sig)
+			if len(sigs) == len(signPIDs) {
 				break signing
 			}
 		}
 	}
+	assert.True(t, restarted, "party 0 should have been restarted")
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	for _, sig := range sigs {
+		ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S))
+		assert.True(t, ok, "ecdsa verify must pass")
+	}
 }
 
-func TestE2EWithHDKeyDerivation(t *testing.T) {
+// presign runs a presigning session of the parties `signPIDs` and returns their presignatures, by index
+func presign(t *testing.T, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) []*Presignature {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	doneCh := make(chan int, len(signPIDs))
+	presigs := make([]*Presignature, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		endCh := make(chan *Presignature, 1)
+		parties = append(parties, NewPresignLocalParty(params, keys[i], outCh, endCh))
+		go func(i int) {
+			presigs[i] = <-endCh
+			doneCh <- i
+		}(i)
+	}
+	for _, P := range parties {
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	for done := 0; done < len(signPIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			go test.RouteMessage(parties, msg, errCh)
+		case <-doneCh:
+			done++
+		}
+	}
+	return presigs
+}
+
+func TestE2EPresignAndOnlineSign(t *testing.T) {
 	setUp("info")
-	threshold := testThreshold
 
 	// PHASE: load keygen fixtures
 	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
 	assert.NoError(t, err, "should load keygen fixtures")
-	assert.Equal(t, testThreshold+1, len(keys))
-	assert.Equal(t, testThreshold+1, len(signPIDs))
 
-	chainCode := make([]byte, 32)
-	max32b := new(big.Int).Lsh(new(big.Int).SetUint64(1), 256)
-	max32b = new(big.Int).Sub(max32b, new(big.Int).SetUint64(1))
-	fillBytes(common.GetRandomPositiveInt(rand.Reader, max32b), chainCode)
+	// PHASE: presigning
+	pools := make([]*PresignaturePool, len(signPIDs))
+	for i, presig := range presign(t, keys, signPIDs) {
+		assert.Equal(t, signPIDs.Keys(), presig.Ks, "the presignature should belong to the parties")
+		pools[i] = NewPresignaturePool()
+		assert.NoError(t, pools[i].Add(presig))
+	}
+	ids := pools[0].IDs()
+	assert.Len(t, ids, 1)
+	for _, pool := range pools {
+		assert.Equal(t, ids, pool.IDs(), "the parties should agree on the presignature")
+	}
 
-	il, extendedChildPk, errorDerivation := derivingPubkeyFromPath(keys[0].ECDSAPub, chainCode, []uint32{12, 209, 3}, btcec.S256())
-	assert.NoErrorf(t, errorDerivation, "there should not be an error deriving the child public key")
+	// the used presignatures of a pool survive a restart
+	bz, err := json.Marshal(pools[0])
+	assert.NoError(t, err)
+	restored := NewPresignaturePool()
+	assert.NoError(t, json.Unmarshal(bz, restored))
+	assert.Equal(t, 1, restored.Len())
 
-	keyDerivationDelta := il
+	// PHASE: online signing
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	msg := big.NewInt(42)
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	presigs := make([]*Presignature, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		presigs[i], err = pools[i].Take(ids[0])
+		assert.NoError(t, err)
+		_, err = pools[i].Take(ids[0])
+		assert.ErrorIs(t, err, ErrPresignatureUsed, "a presignature is taken only once")
+		assert.ErrorIs(t, pools[i].Add(presigs[i]), ErrPresignatureUsed, "a used presignature cannot be added back")
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewOnlineLocalParty(msg, params, presigs[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	pk := ecdsa.PublicKey{
+		Curve: tss.EC(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+	var sigs []*common.SignatureData
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			go test.RouteMessage(parties, msg, errCh)
+		case sig := <-endCh:
+			sigs = append(sigs, sig)
+			if len(sigs) == len(signPIDs) {
+				break signing
+			}
+		}
+	}
+	for _, sig := range sigs {
+		ok := ecdsa.Verify(&pk, msg.Bytes(), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S))
+		assert.True(t, ok, "ecdsa verify must pass")
+	}
 
-	err = UpdatePublicKeyAndAdjustBigXj(keyDerivationDelta, keys, &extendedChildPk.PublicKey, btcec.S256())
-	assert.NoErrorf(t, err, "there should not be an error setting the derived keys")
+	// the nonce shares were wiped, so the presignature cannot sign again even without its pool
+	params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	P := NewOnlineLocalParty(big.NewInt(43), params, presigs[0], outCh, endCh)
+	assert.NotNil(t, P.Start(), "a used presignature should not sign again")
+}
 
-	// PHASE: signing
-	// use a shuffled selection of the list of parties for this test
-	p2pCtx := tss.NewPeerContext(signPIDs)
-	parties := make([]*LocalParty, 0, len(signPIDs))
+func TestOnlineSignChecksSharesBeforeRevealingThem(t *testing.T) {
+	setUp("info")
 
-	errCh := make(chan *tss.Error, len(signPIDs))
-	outCh := make(chan tss.Message, len(signPIDs))
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	presigs := presign(t, keys, signPIDs)
+
+	// party 1 signs with a share of k*x that does not match its presignature
+	presigs[1].Sigma = new(big.Int).Add(presigs[1].Sigma, big.NewInt(1))
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	errCh := make(chan *tss.Error, len(signPIDs)*len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
 	endCh := make(chan *common.SignatureData, len(signPIDs))
+	parties := make([]tss.Party, 0, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewOnlineLocalParty(big.NewInt(42), params, presigs[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
 
-	updater := test.SharedPartyUpdater
+	// every party finds that U != T at round 9, before it sends its share of s
+	failed := make(map[int]bool)
+	for len(failed) < len(signPIDs) {
+		select {
+		case err := <-errCh:
+			assert.Equal(t, 9, err.Round())
+			assert.Contains(t, err.Error(), "U doesn't equal T")
+			failed[err.Victim().Index] = true
+		case msg := <-outCh:
+			_, isShare := msg.(tss.ParsedMessage).Content().(*SignRound9Message)
+			assert.False(t, isShare, "no share of s should be revealed")
+			test.RouteMessage(parties, msg, errCh)
+		case <-endCh:
+			t.Fatal("a signature should not be produced")
+		}
+	}
+}
 
-	// init the parties
+func TestE2EBatchSign(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// the second message is signed with a child key
+	msgs := []*big.Int{big.NewInt(42), big.NewInt(43), big.NewInt(44)}
+	deltas := []*big.Int{nil, big.NewInt(7), nil}
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan []*common.SignatureData, len(signPIDs))
 	for i := 0; i < len(signPIDs); i++ {
 		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
-
-		P := NewLocalPartyWithKDD(big.NewInt(42), params, keys[i], keyDerivationDelta, outCh, endCh, 0).(*LocalParty)
-		parties = append(parties, P)
-		go func(P *LocalParty) {
+		params.SetSessionID([]byte("batch"))
+		parties = append(parties, NewBatchLocalParty(msgs, params, keys[i], deltas, outCh, endCh))
+	}
+	for _, P := range parties {
+		go func(P tss.Party) {
 			if err := P.Start(); err != nil {
 				errCh <- err
 			}
 		}(P)
 	}
 
-	var ended int32
+	// a party sends as many messages as when it signs a single message: n-1 MtA messages in each of rounds 1 and 2,
+	// and one broadcast in each of rounds 1 and 3 to 9
+	sent := 0
+	var batches [][]*common.SignatureData
 signing:
 	for {
 		select {
 		case err := <-errCh:
-			common.Logger.Errorf("Error: %s", err)
 			assert.FailNow(t, err.Error())
-			break signing
-
 		case msg := <-outCh:
+			batch, ok := msg.(tss.ParsedMessage).Content().(*SignBatchMessage)
+			assert.True(t, ok, "the parties should only send batch messages")
+			assert.Len(t, batch.GetMessages(), len(msgs))
+			if msg.GetFrom().Index == 0 {
+				sent++
+			}
 			dest := msg.GetTo()
 			if dest == nil {
 				for _, P := range parties {
-					if P.PartyID().Index == msg.GetFrom().Index {
-						continue
+					if P.PartyID().Index != msg.GetFrom().Index {
+						go test.SharedPartyUpdater(P, msg, errCh)
 					}
-					go updater(P, msg, errCh)
 				}
 			} else {
-				if dest[0].Index == msg.GetFrom().Index {
-					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
-				}
-				go updater(parties[dest[0].Index], msg, errCh)
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
 			}
-
-		case <-endCh:
-			atomic.AddInt32(&ended, 1)
-			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
-				t.Logf("Done. Received signature data from %d participants", ended)
-				R := parties[0].temp.bigR
-				r := parties[0].temp.rx
-				fmt.Printf("sign result: R(%s, %s), r=%s\n", R.X().String(), R.Y().String(), r.String())
-
-				modN := common.ModInt(tss.S256().Params().N)
-
-				// BEGIN check s correctness
-				sumS := big.NewInt(0)
-				for _, p := range parties {
-					sumS = modN.Add(sumS, p.temp.si)
-				}
-				fmt.Printf("S: %s\n", sumS.String())
-				// END check s correctness
-
-				// BEGIN ECDSA verify
-				pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
-				pk := ecdsa.PublicKey{
-					Curve: tss.EC(),
-					X:     pkX,
-					Y:     pkY,
-				}
-				ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(), R.X(), sumS)
-				assert.True(t, ok, "ecdsa verify must pass")
-				t.Log("ECDSA signing test done.")
-				// END ECDSA verify
-
+		case sigs := <-endCh:
+			batches = append(batches, sigs)
+			if len(batches) == len(signPIDs) {
 				break signing
 			}
 		}
 	}
+
+	assert.Equal(t, 2*(len(signPIDs)-1)+8, sent)
+	for _, sigs := range batches {
+		assert.Len(t, sigs, len(msgs))
+		for i, sig := range sigs {
+			pub := keys[0].ECDSAPub
+			if deltas[i] != nil {
+				pub, err = pub.Add(crypto.ScalarBaseMult(tss.S256(), deltas[i]))
+				assert.NoError(t, err)
+			}
+			ok := ecdsa.Verify(pub.ToECDSAPubKey(), msgs[i].Bytes(), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S))
+			assert.True(t, ok, "ecdsa verify must pass for message %d", i)
+			assert.Equal(t, sigs[i].R, batches[0][i].R, "the parties should agree on the signature of message %d", i)
+		}
+	}
+}
+
+func TestBatchGroupsMessagesByRecipient(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	to := func(j int, s int64) tss.Message {
+		meta := tss.MessageRouting{From: pIDs[0], To: []*tss.PartyID{pIDs[j]}}
+		content := &SignRound9Message{S: big.NewInt(s).Bytes()}
+		return tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+	}
+	// the two signings send to the other parties in a different order
+	outs := []chan tss.Message{make(chan tss.Message, 2), make(chan tss.Message, 2)}
+	outs[0] <- to(1, 1)
+	outs[0] <- to(2, 2)
+	outs[1] <- to(2, 4)
+	outs[1] <- to(1, 3)
+	out := make(chan tss.Message, 2)
+	round := &batchBase{params, &batchTempData{outs: outs}, out, nil, true, 1}
+	assert.Nil(t, round.send())
+
+	assert.Len(t, out, 2)
+	// the batches follow the order of the first signing, and carry the message of signing i to Pj at index i
+	for _, j := range []int{1, 2} {
+		msg := <-out
+		assert.Equal(t, []*tss.PartyID{pIDs[j]}, msg.GetTo())
+		batch := msg.(tss.ParsedMessage).Content().(*SignBatchMessage)
+		for i, bz := range batch.GetMessages() {
+			inner, err := tss.ParseWireMessage(bz, pIDs[0], false)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(j+2*i), inner.Content().(*SignRound9Message).UnmarshalS().Int64())
+		}
+	}
+
+	// a signing that sends to other parties than the others is refused
+	outs[0] <- to(1, 1)
+	outs[1] <- to(2, 2)
+	assert.NotNil(t, round.send())
+}
+
+func TestBatchSignNamesTheFailedMessage(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	msgs := []*big.Int{big.NewInt(42), big.NewInt(43), big.NewInt(44)}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	outCh := make(chan tss.Message, len(signPIDs)*len(signPIDs))
+	endCh := make(chan []*common.SignatureData, 1)
+	P := NewBatchLocalParty(msgs, params, keys[0], nil, outCh, endCh)
+	if err := P.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// party 1 sends its round 1 commitment for each message, then a batch that changes the one of message 1
+	batchOf := func(commitments ...int64) tss.ParsedMessage {
+		inner := make([][]byte, len(commitments))
+		for i, c := range commitments {
+			bz, _, err := NewSignRound1Message2(signPIDs[1], big.NewInt(c)).WireBytes()
+			assert.NoError(t, err)
+			inner[i] = bz
+		}
+		return NewSignBatchMessage(nil, signPIDs[1], true, inner)
+	}
+	ok, tErr := P.Update(batchOf(1, 1, 1))
+	assert.True(t, ok)
+	assert.Nil(t, tErr)
+
+	ok, tErr = P.Update(batchOf(1, 2, 1))
+	assert.False(t, ok)
+	if assert.NotNil(t, tErr) {
+		assert.Equal(t, BatchTaskName, tErr.Task())
+		assert.Contains(t, tErr.Error(), "message 1 of the batch")
+		assert.True(t, errors.Is(tErr, tss.ErrEquivocation))
+		assert.Equal(t, []*tss.PartyID{signPIDs[1]}, tErr.Culprits())
+	}
 }
 
 func TestFillTo32BytesInPlace(t *testing.T) {
@@ -15,6 +15,7 @@ import (
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/mta"
 	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -34,6 +35,7 @@ var (
 		(*SignRound7Message)(nil),
 		(*SignRound8Message)(nil),
 		(*SignRound9Message)(nil),
+		(*SignBatchMessage)(nil),
 	}
 )
 
@@ -59,9 +61,13 @@ func NewSignRound1Message1(
 }
 
 func (m *SignRound1Message1) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound1Message1) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.GetC()) &&
-		common.NonEmptyMultiBytes(m.GetRangeProofAlice(), mta.RangeProofAliceBytesParts)
+		common.NonEmptyBytesMaxBits(m.GetC(), keygen.MaxCiphertextBits) &&
+		common.NonEmptyMultiBytesMaxBits(m.GetRangeProofAlice(), keygen.MaxMtAProofBits(ec), mta.RangeProofAliceBytesParts)
 }
 
 func (m *SignRound1Message1) UnmarshalC() *big.Int {
@@ -91,7 +97,7 @@ func NewSignRound1Message2(
 
 func (m *SignRound1Message2) ValidateBasic() bool {
 	return m.Commitment != nil &&
-		common.NonEmptyBytes(m.GetCommitment())
+		common.NonEmptyBytesMaxBits(m.GetCommitment(), keygen.MaxCommitmentBits())
 }
 
 func (m *SignRound1Message2) UnmarshalCommitment() *big.Int {
@@ -125,11 +131,15 @@ func NewSignRound2Message(
 }
 
 func (m *SignRound2Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound2Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.C1) &&
-		common.NonEmptyBytes(m.C2) &&
-		common.NonEmptyMultiBytes(m.ProofBob, mta.ProofBobBytesParts) &&
-		common.NonEmptyMultiBytes(m.ProofBobWc, mta.ProofBobWCBytesParts)
+		common.NonEmptyBytesMaxBits(m.C1, keygen.MaxCiphertextBits) &&
+		common.NonEmptyBytesMaxBits(m.C2, keygen.MaxCiphertextBits) &&
+		common.NonEmptyMultiBytesMaxBits(m.ProofBob, keygen.MaxMtAProofBits(ec), mta.ProofBobBytesParts) &&
+		common.NonEmptyMultiBytesMaxBits(m.ProofBobWc, keygen.MaxMtAProofBits(ec), mta.ProofBobWCBytesParts)
 }
 
 func (m *SignRound2Message) UnmarshalProofBob() (*mta.ProofBob, error) {
@@ -158,8 +168,12 @@ func NewSignRound3Message(
 }
 
 func (m *SignRound3Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound3Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.Theta)
+		common.NonEmptyBytesMaxBits(m.Theta, keygen.MaxScalarBits(ec))
 }
 
 // ----- //
@@ -185,11 +199,15 @@ func NewSignRound4Message(
 }
 
 func (m *SignRound4Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound4Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
-		common.NonEmptyBytes(m.ProofAlphaX) &&
-		common.NonEmptyBytes(m.ProofAlphaY) &&
-		common.NonEmptyBytes(m.ProofT)
+		common.NonEmptyMultiBytesMaxBits(m.DeCommitment, keygen.MaxDeCommitmentBits(ec), 3) &&
+		common.NonEmptyBytesMaxBits(m.ProofAlphaX, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.ProofAlphaY, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.ProofT, keygen.MaxScalarBits(ec))
 }
 
 func (m *SignRound4Message) UnmarshalDeCommitment() []*big.Int {
@@ -230,7 +248,7 @@ func NewSignRound5Message(
 
 func (m *SignRound5Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.Commitment)
+		common.NonEmptyBytesMaxBits(m.Commitment, keygen.MaxCommitmentBits())
 }
 
 func (m *SignRound5Message) UnmarshalCommitment() *big.Int {
@@ -265,15 +283,19 @@ func NewSignRound6Message(
 }
 
 func (m *SignRound6Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound6Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 5) &&
-		common.NonEmptyBytes(m.ProofAlphaX) &&
-		common.NonEmptyBytes(m.ProofAlphaY) &&
-		common.NonEmptyBytes(m.ProofT) &&
-		common.NonEmptyBytes(m.VProofAlphaX) &&
-		common.NonEmptyBytes(m.VProofAlphaY) &&
-		common.NonEmptyBytes(m.VProofT) &&
-		common.NonEmptyBytes(m.VProofU)
+		common.NonEmptyMultiBytesMaxBits(m.DeCommitment, keygen.MaxDeCommitmentBits(ec), 5) &&
+		common.NonEmptyBytesMaxBits(m.ProofAlphaX, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.ProofAlphaY, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.ProofT, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.VProofAlphaX, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.VProofAlphaY, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.VProofT, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.VProofU, keygen.MaxScalarBits(ec))
 }
 
 func (m *SignRound6Message) UnmarshalDeCommitment() []*big.Int {
@@ -329,7 +351,7 @@ func NewSignRound7Message(
 
 func (m *SignRound7Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.Commitment)
+		common.NonEmptyBytesMaxBits(m.Commitment, keygen.MaxCommitmentBits())
 }
 
 func (m *SignRound7Message) UnmarshalCommitment() *big.Int {
@@ -355,8 +377,12 @@ func NewSignRound8Message(
 }
 
 func (m *SignRound8Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound8Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 5)
+		common.NonEmptyMultiBytesMaxBits(m.DeCommitment, keygen.MaxDeCommitmentBits(ec), 5)
 }
 
 func (m *SignRound8Message) UnmarshalDeCommitment() []*big.Int {
@@ -382,10 +408,39 @@ func NewSignRound9Message(
 }
 
 func (m *SignRound9Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound9Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.S)
+		common.NonEmptyBytesMaxBits(m.S, keygen.MaxScalarBits(ec))
 }
 
 func (m *SignRound9Message) UnmarshalS() *big.Int {
 	return new(big.Int).SetBytes(m.S)
 }
+
+// ----- //
+
+func NewSignBatchMessage(
+	to []*tss.PartyID,
+	from *tss.PartyID,
+	isBroadcast bool,
+	msgs [][]byte,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          to,
+		IsBroadcast: isBroadcast,
+	}
+	content := &SignBatchMessage{
+		Messages: msgs,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *SignBatchMessage) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyMultiBytes(m.Messages)
+}
@@ -0,0 +1,77 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// NewOnlineLocalParty returns a party that signs `msg` with `presig`; every party that made the presignature must sign
+// with it, with the same Parameters parties. The party runs rounds 5 to 9 of signing, as GG18 cannot safely sign in one
+// round: the MtA rounds of presigning do not prove that a peer answered honestly, so the share of s of a party, which
+// may then leak its key share, is only revealed once phase 5 has checked that the shares add up to a valid signature.
+// That check commits to values derived from the shares of s and opens them, and each of its steps needs the commitments
+// of the one before, so it takes rounds 5 to 8 and the shares are revealed in round 9. None of those rounds uses Paillier.
+// The party wipes the nonce shares of `presig` once it has used them, so that a presignature cannot sign twice, but a
+// copy of it must still be kept from being used again, e.g. by a PresignaturePool.
+func NewOnlineLocalParty(
+	msg *big.Int,
+	params *tss.Parameters,
+	presig *Presignature,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) tss.Party {
+	// only the public key of the save data is used from round 5
+	key := keygen.LocalPartySaveData{}
+	if presig != nil {
+		key.ECDSAPub = presig.ECDSAPub
+	}
+	p := newLocalParty(msg, params, key, nil, out, end, fullBytesLen...)
+	p.presig = presig
+	return p
+}
+
+// newOnlineRound5 returns round 5 of signing for a party that starts from a presignature
+func newOnlineRound5(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+	return &round5{&round4{&round3{&round2{&round1{
+		&base{params, key, data, temp, out, end, nil, make([]bool, len(params.Parties().IDs())), false, 5},
+	}}}}}
+}
+
+// prepareOnline checks `presig` and takes the state of round 5 from it
+func (round *round5) prepareOnline(presig *Presignature) error {
+	if presig.K == nil || presig.Sigma == nil || presig.R == nil || presig.ECDSAPub == nil {
+		return errors.New("the presignature is incomplete or was already used")
+	}
+	Ps := round.Parties().IDs()
+	if len(presig.Ks) != len(Ps) {
+		return fmt.Errorf("the presignature was made by %d parties, not %d", len(presig.Ks), len(Ps))
+	}
+	for j, Pj := range Ps {
+		if presig.Ks[j].Cmp(Pj.KeyInt()) != 0 {
+			return errors.New("the presignature was made by other parties")
+		}
+	}
+	if round.temp.m == nil || round.temp.m.Cmp(round.Params().EC().Params().N) >= 0 {
+		return errors.New("hashed message is not valid")
+	}
+	round.temp.ssid = presig.SSID
+	round.temp.k = presig.K
+	round.temp.sigma = presig.Sigma
+	round.temp.bigR = presig.R
+
+	// the nonce shares are spent now; wipe them so that this presignature cannot sign another message
+	presig.K, presig.Sigma = nil, nil
+	return nil
+}
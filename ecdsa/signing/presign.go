@@ -0,0 +1,102 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the length of a Presignature ID, a SHA-512/256 digest
+const presignatureIDLen = 32
+
+type (
+	// Presignature is the output of a presigning party: its shares of a signing nonce that was agreed before the message
+	// was known. Sign a message with it with NewOnlineLocalParty. A presignature must never be used to sign twice, as
+	// two signatures with the same nonce reveal the key; keep it in a PresignaturePool, which enforces this.
+	Presignature struct {
+		// ID is the same at every party of the presigning session
+		ID []byte
+		// Ks are the keys of the parties that presigned, by index; the same parties must sign with the presignature
+		Ks []*big.Int
+		// SSID is the session id of the presigning session, which the proofs of the online rounds are bound to
+		SSID []byte
+		// K and Sigma are the additive shares of this party of the nonce k and of k*x
+		K,
+		Sigma *big.Int
+		// R = g^(k^-1)
+		R        *crypto.ECPoint
+		ECDSAPub *crypto.ECPoint
+	}
+)
+
+// NewPresignLocalParty returns a party that runs the rounds of signing that do not depend on the message, including
+// the MtA rounds, and sends its Presignature to `end` once R is known.
+func NewPresignLocalParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *Presignature,
+) tss.Party {
+	p := NewLocalPartyWithKDD(nil, params, key, nil, out, nil).(*LocalParty)
+	p.presignEnd = end
+	return p
+}
+
+// ----- //
+
+func (round *presignFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	R, err := round.computeR()
+	if err != nil {
+		return err
+	}
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+	presig := &Presignature{
+		ID:       common.SHA512_256(round.temp.ssid, R.X().Bytes(), R.Y().Bytes()),
+		Ks:       round.Parties().IDs().Keys(),
+		SSID:     round.temp.ssid,
+		K:        round.temp.k,
+		Sigma:    round.temp.sigma,
+		R:        R,
+		ECDSAPub: round.key.ECDSAPub,
+	}
+
+	// clear temp.w and temp.k from memory, lint ignore
+	round.temp.w = zero
+	round.temp.k = zero
+
+	round.presignEnd <- presig
+	return nil
+}
+
+func (round *presignFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *presignFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *presignFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
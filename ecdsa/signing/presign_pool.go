@@ -0,0 +1,166 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrPresignatureNotFound = errors.New("presignature not found")
+	ErrPresignatureUsed     = errors.New("presignature was already used")
+)
+
+type (
+	// PresignaturePool keeps the presignatures of a party until they are used, and remembers the IDs of the ones that
+	// were taken so that none of them can be used twice. It is safe for concurrent use.
+	// Persist it with json.Marshal after every Take, before signing, to keep that guarantee across restarts.
+	// The JSON holds the nonce shares K and Sigma of the presignatures in the clear; they must be kept as secret as the
+	// key, so encrypt it as you would a LocalPartySaveData before storing it.
+	PresignaturePool struct {
+		mtx       sync.Mutex
+		available map[string]*Presignature
+		order     []string
+		used      map[string]struct{}
+	}
+
+	// presignaturePoolJSON is the JSON form of a PresignaturePool
+	presignaturePoolJSON struct {
+		Available []*Presignature
+		Used      [][]byte
+	}
+)
+
+func NewPresignaturePool() *PresignaturePool {
+	return &PresignaturePool{
+		available: make(map[string]*Presignature),
+		order:     make([]string, 0),
+		used:      make(map[string]struct{}),
+	}
+}
+
+// Add puts a presignature in the pool; it is refused if the pool already has it or has seen it used
+func (pool *PresignaturePool) Add(presig *Presignature) error {
+	if presig == nil || len(presig.ID) != presignatureIDLen || presig.K == nil || presig.Sigma == nil {
+		return errors.New("cannot add an incomplete presignature")
+	}
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	id := string(presig.ID)
+	if _, ok := pool.used[id]; ok {
+		return fmt.Errorf("%w: %s", ErrPresignatureUsed, hex.EncodeToString(presig.ID))
+	}
+	if _, ok := pool.available[id]; ok {
+		return fmt.Errorf("presignature %s is already in the pool", hex.EncodeToString(presig.ID))
+	}
+	pool.available[id] = presig
+	pool.order = append(pool.order, id)
+	return nil
+}
+
+// Take removes the presignature `id` from the pool and marks it as used. It fails if that presignature was taken before.
+func (pool *PresignaturePool) Take(id []byte) (*Presignature, error) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return pool.take(string(id))
+}
+
+// TakeNext takes the presignature that was added first, so that the parties of a key take the same one when
+// they have added the same presignatures in the same order
+func (pool *PresignaturePool) TakeNext() (*Presignature, error) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	if len(pool.order) == 0 {
+		return nil, ErrPresignatureNotFound
+	}
+	return pool.take(pool.order[0])
+}
+
+// IDs returns the IDs of the presignatures that can still be taken, in the order they were added
+func (pool *PresignaturePool) IDs() [][]byte {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	ids := make([][]byte, len(pool.order))
+	for i, id := range pool.order {
+		ids[i] = []byte(id)
+	}
+	return ids
+}
+
+// Len returns the number of presignatures that can still be taken
+func (pool *PresignaturePool) Len() int {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return len(pool.order)
+}
+
+// Used reports whether the presignature `id` was taken from the pool
+func (pool *PresignaturePool) Used(id []byte) bool {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	_, ok := pool.used[string(id)]
+	return ok
+}
+
+func (pool *PresignaturePool) MarshalJSON() ([]byte, error) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	out := presignaturePoolJSON{
+		Available: make([]*Presignature, 0, len(pool.order)),
+		Used:      make([][]byte, 0, len(pool.used)),
+	}
+	for _, id := range pool.order {
+		out.Available = append(out.Available, pool.available[id])
+	}
+	for id := range pool.used {
+		out.Used = append(out.Used, []byte(id))
+	}
+	return json.Marshal(out)
+}
+
+func (pool *PresignaturePool) UnmarshalJSON(payload []byte) error {
+	in := new(presignaturePoolJSON)
+	if err := json.Unmarshal(payload, in); err != nil {
+		return err
+	}
+	restored := NewPresignaturePool()
+	for _, id := range in.Used {
+		restored.used[string(id)] = struct{}{}
+	}
+	for _, presig := range in.Available {
+		if err := restored.Add(presig); err != nil {
+			return err
+		}
+	}
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	pool.available, pool.order, pool.used = restored.available, restored.order, restored.used
+	return nil
+}
+
+func (pool *PresignaturePool) take(id string) (*Presignature, error) {
+	if _, ok := pool.used[id]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrPresignatureUsed, hex.EncodeToString([]byte(id)))
+	}
+	presig, ok := pool.available[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPresignatureNotFound, hex.EncodeToString([]byte(id)))
+	}
+	delete(pool.available, id)
+	for i, o := range pool.order {
+		if o == id {
+			pool.order = append(pool.order[:i], pool.order[i+1:]...)
+			break
+		}
+	}
+	pool.used[id] = struct{}{}
+	return presig, nil
+}
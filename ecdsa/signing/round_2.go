@@ -32,6 +32,11 @@ func (round *round2) Start() *tss.Error {
 	wg := sync.WaitGroup{}
 	wg.Add((len(round.Parties().IDs()) - 1) * 2)
 	ContextI := append(round.temp.ssid, new(big.Int).SetUint64(uint64(i)).Bytes()...)
+	// each goroutine draws from its own source, so that a seeded Rand gives the same values in every run
+	rands, err := round.ForkRand(len(round.Parties().IDs()) * 2)
+	if err != nil {
+		return round.WrapError(err)
+	}
 	for j, Pj := range round.Parties().IDs() {
 		if j == i {
 			continue
@@ -58,12 +63,13 @@ func (round *round2) Start() *tss.Error {
 				round.key.NTildej[i],
 				round.key.H1j[i],
 				round.key.H2j[i],
-				round.Rand(),
+				rands[2*j],
 			)
 			// should be thread safe as these are pre-allocated
 			round.temp.betas[j] = beta
 			round.temp.c1jis[j] = c1ji
 			round.temp.pi1jis[j] = pi1ji
+			round.observeProof(Pj, tss.ProofMtARange, err == nil)
 			if err != nil {
 				errChs <- round.WrapError(err, Pj)
 			}
@@ -91,11 +97,12 @@ func (round *round2) Start() *tss.Error {
 				round.key.H1j[i],
 				round.key.H2j[i],
 				round.temp.bigWs[i],
-				round.Rand(),
+				rands[2*j+1],
 			)
 			round.temp.vs[j] = v
 			round.temp.c2jis[j] = c2ji
 			round.temp.pi2jis[j] = pi2ji
+			round.observeProof(Pj, tss.ProofMtARange, err == nil)
 			if err != nil {
 				errChs <- round.WrapError(err, Pj)
 			}
@@ -109,7 +116,7 @@ func (round *round2) Start() *tss.Error {
 		culprits = append(culprits, err.Culprits()...)
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Bob_mid or Bob_mid_wc"), culprits...)
+		return round.WrapError(tss.NewProofError(tss.ProofMtARange, errors.New("failed to calculate Bob_mid or Bob_mid_wc")), culprits...)
 	}
 	// create and send messages
 	for j, Pj := range round.Parties().IDs() {
@@ -118,6 +125,9 @@ func (round *round2) Start() *tss.Error {
 		}
 		r2msg := NewSignRound2Message(
 			Pj, round.PartyID(), round.temp.c1jis[j], round.temp.pi1jis[j], round.temp.c2jis[j], round.temp.pi2jis[j])
+		if err := round.StampMessage(r2msg); err != nil {
+			return round.WrapError(err)
+		}
 		round.out <- r2msg
 	}
 	return nil
@@ -60,8 +60,9 @@ func (round *round3) Start() *tss.Error {
 				round.key.NTildej[i],
 				round.key.PaillierSK)
 			alphas[j] = alphaIj
+			round.observeProof(Pj, tss.ProofMtABob, err == nil)
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(tss.NewProofError(tss.ProofMtABob, err), Pj)
 			}
 		}(j, Pj)
 		// Alice_end_wc
@@ -86,8 +87,9 @@ func (round *round3) Start() *tss.Error {
 				round.key.H2j[i],
 				round.key.PaillierSK)
 			us[j] = uIj
+			round.observeProof(Pj, tss.ProofMtABobWC, err == nil)
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(tss.NewProofError(tss.ProofMtABobWC, err), Pj)
 			}
 		}(j, Pj)
 	}
@@ -96,11 +98,15 @@ func (round *round3) Start() *tss.Error {
 	wg.Wait()
 	close(errChs)
 	culprits := make([]*tss.PartyID, 0, len(round.Parties().IDs()))
+	var cause error = errors.New("failed to calculate Alice_end or Alice_end_wc")
 	for err := range errChs {
 		culprits = append(culprits, err.Culprits()...)
+		if proof := err.Proof(); proof != "" {
+			cause = tss.NewProofError(proof, errors.New("failed to calculate Alice_end or Alice_end_wc"))
+		}
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Alice_end or Alice_end_wc"), culprits...)
+		return round.WrapError(cause, culprits...)
 	}
 
 	modN := common.ModInt(round.Params().EC().Params().N)
@@ -119,6 +125,9 @@ func (round *round3) Start() *tss.Error {
 	round.temp.sigma = sigma
 	r3msg := NewSignRound3Message(round.PartyID(), thelta)
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
+	if err := round.StampMessage(r3msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r3msg
 
 	return nil
@@ -50,6 +50,9 @@ func (round *round4) Start() *tss.Error {
 	round.temp.thetaInverse = thetaInverse
 	r4msg := NewSignRound4Message(round.PartyID(), round.temp.deCommit, piGamma)
 	round.temp.signRound4Messages[round.PartyID().Index] = r4msg
+	if err := round.StampMessage(r4msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r4msg
 
 	return nil
@@ -79,5 +82,8 @@ func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
 
 func (round *round4) NextRound() tss.Round {
 	round.started = false
+	if round.presignEnd != nil {
+		return &presignFinalization{round}
+	}
 	return &round5{round}
 }
@@ -37,6 +37,9 @@ func (round *round6) Start() *tss.Error {
 
 	r6msg := NewSignRound6Message(round.PartyID(), round.temp.DPower, piAi, piV)
 	round.temp.signRound6Messages[round.PartyID().Index] = r6msg
+	if err := round.StampMessage(r6msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r6msg
 	return nil
 }
@@ -53,12 +53,16 @@ func (round *round7) Start() *tss.Error {
 		}
 		bigAjs[j] = bigAj
 		pijA, err := r6msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil || !pijA.Verify(ContextJ, bigAj) {
-			return round.WrapError(errors.New("schnorr verify for Aj failed"), Pj)
+		ok = err == nil && pijA.Verify(ContextJ, bigAj)
+		round.observeProof(Pj, tss.ProofSchnorr, ok)
+		if !ok {
+			return round.WrapError(tss.NewProofError(tss.ProofSchnorr, errors.New("schnorr verify for Aj failed")), Pj)
 		}
 		pijV, err := r6msg.UnmarshalZKVProof(round.Params().EC())
-		if err != nil || !pijV.Verify(ContextJ, bigVj, round.temp.bigR) {
-			return round.WrapError(errors.New("vverify for Vj failed"), Pj)
+		ok = err == nil && pijV.Verify(ContextJ, bigVj, round.temp.bigR)
+		round.observeProof(Pj, tss.ProofSchnorrV, ok)
+		if !ok {
+			return round.WrapError(tss.NewProofError(tss.ProofSchnorrV, errors.New("vverify for Vj failed")), Pj)
 		}
 	}
 
@@ -86,6 +90,9 @@ func (round *round7) Start() *tss.Error {
 	cmt := commitments.NewHashCommitment(round.Rand(), UiX, UiY, TiX, TiY)
 	r7msg := NewSignRound7Message(round.PartyID(), cmt.C)
 	round.temp.signRound7Messages[round.PartyID().Index] = r7msg
+	if err := round.StampMessage(r7msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r7msg
 	round.temp.DTelda = cmt.D
 
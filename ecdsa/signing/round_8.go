@@ -22,6 +22,9 @@ func (round *round8) Start() *tss.Error {
 
 	r8msg := NewSignRound8Message(round.PartyID(), round.temp.DTelda)
 	round.temp.signRound8Messages[round.PartyID().Index] = r8msg
+	if err := round.StampMessage(r8msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r8msg
 
 	return nil
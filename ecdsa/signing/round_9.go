@@ -46,6 +46,9 @@ func (round *round9) Start() *tss.Error {
 
 	r9msg := NewSignRound9Message(round.PartyID(), round.temp.si)
 	round.temp.signRound9Messages[round.PartyID().Index] = r9msg
+	if err := round.StampMessage(r9msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r9msg
 	return nil
 }
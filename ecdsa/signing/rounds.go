@@ -23,14 +23,16 @@ const (
 type (
 	base struct {
 		*tss.Parameters
-		key     *keygen.LocalPartySaveData
-		data    *common.SignatureData
-		temp    *localTempData
-		out     chan<- tss.Message
-		end     chan<- *common.SignatureData
-		ok      []bool // `ok` tracks parties which have been verified by Update()
-		started bool
-		number  int
+		key  *keygen.LocalPartySaveData
+		data *common.SignatureData
+		temp *localTempData
+		out  chan<- tss.Message
+		end  chan<- *common.SignatureData
+		// set for a presigning party, which ends after round 4 with a Presignature
+		presignEnd chan<- *Presignature
+		ok         []bool // `ok` tracks parties which have been verified by Update()
+		started    bool
+		number     int
 	}
 	round1 struct {
 		*base
@@ -62,6 +64,9 @@ type (
 	finalization struct {
 		*round9
 	}
+	presignFinalization struct {
+		*round4
+	}
 )
 
 var (
@@ -75,6 +80,7 @@ var (
 	_ tss.Round = (*round8)(nil)
 	_ tss.Round = (*round9)(nil)
 	_ tss.Round = (*finalization)(nil)
+	_ tss.Round = (*presignFinalization)(nil)
 )
 
 // ----- //
@@ -117,6 +123,11 @@ func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
 }
 
+// observeProof reports the outcome of verifying a proof sent by Pj to the Observer on the Parameters
+func (round *base) observeProof(Pj *tss.PartyID, proof tss.ProofType, ok bool) {
+	tss.ObserveProof(round.Params(), TaskName, round.number, Pj, proof, ok)
+}
+
 // ----- //
 
 // `ok` tracks parties which have been verified by Update()
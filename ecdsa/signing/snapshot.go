@@ -0,0 +1,180 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/mta"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// finalization never waits for messages, so a running party is always in one of these rounds
+const lastMessageRound = 9
+
+type (
+	// partySnapshot is the JSON form of a signing party that is waiting for the messages of a round
+	partySnapshot struct {
+		Round int
+		OK    []bool
+
+		Messages [][]*tss.SnapshotMessage
+
+		W, M, K, Theta, ThetaInverse, Sigma, KeyDerivationDelta, Gamma *big.Int
+		FullBytesLen                                                   int
+		Cis                                                            []*big.Int
+		BigWs                                                          []*crypto.ECPoint
+		PointGamma                                                     *crypto.ECPoint
+		DeCommit                                                       cmt.HashDeCommitment
+
+		Betas, C1jis, C2jis, Vs []*big.Int
+		Pi1jis                  []*mta.ProofBob
+		Pi2jis                  []*mta.ProofBobWC
+
+		Li, Si, Rx, Ry, Roi *big.Int
+		BigR, BigAi, BigVi  *crypto.ECPoint
+		DPower              cmt.HashDeCommitment
+
+		Ui, Ti *crypto.ECPoint
+		DTelda cmt.HashDeCommitment
+
+		SSIDNonce *big.Int
+		SSID      []byte
+	}
+
+	// every round embeds base, which holds the state that is shared by the rounds
+	baseRound interface {
+		roundBase() *base
+	}
+)
+
+func (store *localMessageStore) all() []*[]tss.ParsedMessage {
+	return []*[]tss.ParsedMessage{
+		&store.signRound1Message1s,
+		&store.signRound1Message2s,
+		&store.signRound2Messages,
+		&store.signRound3Messages,
+		&store.signRound4Messages,
+		&store.signRound5Messages,
+		&store.signRound6Messages,
+		&store.signRound7Messages,
+		&store.signRound8Messages,
+		&store.signRound9Messages,
+	}
+}
+
+// Snapshot serialises the state of a running party: its round, its secret temp data and the messages it has stored.
+// A party rebuilt from it with RestoreLocalParty continues from the same round after a restart.
+// The snapshot contains the nonces of this signing session and must be kept as secret as the key.
+func (p *LocalParty) Snapshot() ([]byte, error) {
+	if p.presignEnd != nil || p.presig != nil {
+		return nil, errors.New("a presigning or online signing party cannot be snapshotted")
+	}
+	return tss.BaseSnapshot(p, func(round tss.Round) ([]byte, error) {
+		t := &p.temp
+		snap := &partySnapshot{
+			Round: round.RoundNumber(),
+			OK:    append([]bool{}, round.(baseRound).roundBase().ok...),
+
+			W: t.w, M: t.m, K: t.k, Theta: t.theta, ThetaInverse: t.thetaInverse, Sigma: t.sigma,
+			KeyDerivationDelta: t.keyDerivationDelta, Gamma: t.gamma,
+			FullBytesLen: t.fullBytesLen, Cis: t.cis, BigWs: t.bigWs, PointGamma: t.pointGamma, DeCommit: t.deCommit,
+
+			Betas: t.betas, C1jis: t.c1jis, C2jis: t.c2jis, Vs: t.vs, Pi1jis: t.pi1jis, Pi2jis: t.pi2jis,
+
+			Li: t.li, Si: t.si, Rx: t.rx, Ry: t.ry, Roi: t.roi, BigR: t.bigR, BigAi: t.bigAi, BigVi: t.bigVi, DPower: t.DPower,
+
+			Ui: t.Ui, Ti: t.Ti, DTelda: t.DTelda,
+
+			SSIDNonce: t.ssidNonce, SSID: t.ssid,
+		}
+		for _, store := range t.all() {
+			msgs, err := tss.SnapshotMessages(*store)
+			if err != nil {
+				return nil, err
+			}
+			snap.Messages = append(snap.Messages, msgs)
+		}
+		return json.Marshal(snap)
+	})
+}
+
+// RestoreLocalParty rebuilds a party from a Snapshot, using the same parameters and key that the party was created with.
+// Call Resume rather than Start on the returned party; it will process the stored messages and carry on from there.
+func RestoreLocalParty(
+	snapshot []byte,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) (*LocalParty, error) {
+	snap := new(partySnapshot)
+	if err := json.Unmarshal(snapshot, snap); err != nil {
+		return nil, err
+	}
+	partyCount := len(params.Parties().IDs())
+	if snap.Round < 1 || lastMessageRound < snap.Round {
+		return nil, fmt.Errorf("snapshot has an invalid round number %d", snap.Round)
+	}
+	if len(snap.OK) != partyCount || len(snap.Cis) != partyCount || len(snap.BigWs) != partyCount ||
+		len(snap.Betas) != partyCount || len(snap.C1jis) != partyCount || len(snap.C2jis) != partyCount ||
+		len(snap.Vs) != partyCount || len(snap.Pi1jis) != partyCount || len(snap.Pi2jis) != partyCount {
+		return nil, fmt.Errorf("snapshot does not match the party count %d", partyCount)
+	}
+	p := NewLocalPartyWithKDD(snap.M, params, key, snap.KeyDerivationDelta, out, end, snap.FullBytesLen).(*LocalParty)
+	t := &p.temp
+	stores := t.all()
+	if len(snap.Messages) != len(stores) {
+		return nil, errors.New("snapshot has an unexpected number of message stores")
+	}
+	for i, store := range stores {
+		if err := tss.RestoreMessages(*store, snap.Messages[i]); err != nil {
+			return nil, err
+		}
+	}
+	t.w, t.k, t.theta, t.thetaInverse, t.sigma, t.gamma = snap.W, snap.K, snap.Theta, snap.ThetaInverse, snap.Sigma, snap.Gamma
+	t.cis, t.bigWs, t.pointGamma, t.deCommit = snap.Cis, snap.BigWs, snap.PointGamma, snap.DeCommit
+	t.betas, t.c1jis, t.c2jis, t.vs, t.pi1jis, t.pi2jis = snap.Betas, snap.C1jis, snap.C2jis, snap.Vs, snap.Pi1jis, snap.Pi2jis
+	t.li, t.si, t.rx, t.ry, t.roi = snap.Li, snap.Si, snap.Rx, snap.Ry, snap.Roi
+	t.bigR, t.bigAi, t.bigVi, t.DPower = snap.BigR, snap.BigAi, snap.BigVi, snap.DPower
+	t.Ui, t.Ti, t.DTelda = snap.Ui, snap.Ti, snap.DTelda
+	t.ssidNonce, t.ssid = snap.SSIDNonce, snap.SSID
+
+	round := p.FirstRound()
+	for n := 1; n < snap.Round; n++ {
+		round = round.NextRound()
+	}
+	base := round.(baseRound).roundBase()
+	base.number, base.started = snap.Round, true
+	copy(base.ok, snap.OK)
+	p.resumeRound = round
+	return p, nil
+}
+
+func (p *LocalParty) Resume() *tss.Error {
+	return p.ResumeWithContext(context.Background())
+}
+
+// ResumeWithContext resumes a party returned by RestoreLocalParty; see StartWithContext for the meaning of ctx
+func (p *LocalParty) ResumeWithContext(ctx context.Context) *tss.Error {
+	if p.resumeRound == nil {
+		return p.WrapError(errors.New("could not resume. this party was not restored from a snapshot"))
+	}
+	return tss.BaseResumeWithContext(ctx, p, p.resumeRound, TaskName)
+}
+
+func (round *base) roundBase() *base {
+	return round
+}
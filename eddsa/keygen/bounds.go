@@ -0,0 +1,54 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ecies"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// The maximum bit lengths of the integers in the EdDSA messages, which ValidateBasic enforces so that a message with
+// oversized integers is rejected, and its sender blamed, before any of its points or proofs are checked.
+
+// MaxScalarBits bounds a scalar or a point coordinate on the curve `ec`, or on any of the registered curves when `ec`
+// is nil
+func MaxScalarBits(ec elliptic.Curve) int {
+	if ec == nil {
+		return tss.MaxCurveBitLen()
+	}
+	return tss.CurveBitLen(ec)
+}
+
+// MaxCommitmentBits bounds a hash commitment and the randomness of its decommitment
+func MaxCommitmentBits() int {
+	return cmt.HashLength
+}
+
+// MaxDeCommitmentBits bounds the parts of a decommitment: its randomness and the point coordinates it opens
+func MaxDeCommitmentBits(ec elliptic.Curve) int {
+	if scalarBits := MaxScalarBits(ec); cmt.HashLength < scalarBits {
+		return scalarBits
+	}
+	return cmt.HashLength
+}
+
+// MaxEncryptedShareBytes bounds a share that was encrypted to the identity key of its recipient
+func MaxEncryptedShareBytes(ec elliptic.Curve) int {
+	return (MaxScalarBits(ec)+7)/8 + ecies.MaxOverhead
+}
+
+// ValidShareBytes checks a share that is sent either in the clear or encrypted to its recipient, but not both
+func ValidShareBytes(share, encryptedShare []byte, ec elliptic.Curve) bool {
+	if len(encryptedShare) == 0 {
+		return common.NonEmptyBytesMaxBits(share, MaxScalarBits(ec))
+	}
+	return len(share) == 0 && len(encryptedShare) <= MaxEncryptedShareBytes(ec)
+}
@@ -211,6 +211,105 @@ keygen:
 	}
 }
 
+func TestValidateShare(t *testing.T) {
+	keys, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := func(i int) *tss.Parameters {
+		return tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+	}
+	i := 0
+	for j, Pj := range pIDs {
+		if Pj.KeyInt().Cmp(keys[0].ShareID) == 0 {
+			i = j
+		}
+	}
+	assert.NoError(t, keys[0].ValidateShare(params(i)))
+	assert.Error(t, keys[0].ValidateShare(params((i+1)%len(pIDs))), "the share of another party")
+
+	key := keys[0]
+	key.Xi = new(big.Int).Add(key.Xi, big.NewInt(1))
+	assert.Error(t, key.ValidateShare(params(i)), "wrong secret share")
+	key = keys[0]
+	key.BigXj = append([]*crypto.ECPoint{}, keys[0].BigXj...)
+	key.BigXj[(i+1)%len(key.BigXj)] = crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(1))
+	assert.Error(t, key.ValidateShare(params(i)), "wrong public share")
+	key = keys[0]
+	key.EDDSAPub = crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(1))
+	assert.Error(t, key.ValidateShare(params(i)), "wrong public key")
+}
+
+func TestScalarsAreBoundedByTheCurveOfTheSession(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P := NewLocalParty(params, make(chan tss.Message, len(pIDs)), nil)
+
+	// a share of 256 bits fits secp256k1, the largest registered curve, but no integer of edwards25519
+	msg := NewKGRound2Message1(pIDs[0], pIDs[1], &vss.Share{Share: new(big.Int).Lsh(big.NewInt(1), 255)})
+	assert.True(t, msg.ValidateBasic())
+	ok, err := P.ValidateMessage(msg)
+	assert.False(t, ok)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, []*tss.PartyID{pIDs[1]}, err.Culprits())
+	}
+
+	msg = NewKGRound2Message1(pIDs[0], pIDs[1], &vss.Share{Share: big.NewInt(1)})
+	ok, err = P.ValidateMessage(msg)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestEncryptedShares(t *testing.T) {
+	setUp("info")
+
+	session := test.NewSession(tss.Edwards(), tss.GenerateTestPartyIDs(testParticipants), testThreshold,
+		func(_ int, params *tss.Parameters) { params.SetEncryptShares() })
+	assert.NoError(t, session.SetIdentityKeys())
+	n := len(session.PartyIDs)
+	outCh := make(chan tss.Message, n*n*3)
+	endCh := make(chan *LocalPartySaveData, n)
+	errCh := make(chan *tss.Error, n*n*3)
+	parties := make([]tss.Party, 0, n)
+	for _, params := range session.Params {
+		parties = append(parties, NewLocalParty(params, outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shares := 0
+	for ended := 0; ended < n; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			if r2msg1, ok := msg.(tss.ParsedMessage).Content().(*KGRound2Message1); ok {
+				assert.Empty(t, r2msg1.GetShare())
+				assert.NotEmpty(t, r2msg1.GetEncryptedShare())
+				from, to := msg.GetFrom(), msg.GetTo()[0]
+				if shares++; shares == 1 {
+					// only the recipient can open the share
+					other := (to.Index + 1) % n
+					if other == from.Index {
+						other = (to.Index + 2) % n
+					}
+					_, err := r2msg1.OpenShare(session.Params[other], from)
+					assert.Error(t, err)
+					// and a share in the clear is refused
+					plain := NewKGRound2Message1(to, from, &vss.Share{Share: big.NewInt(1)})
+					_, err = plain.Content().(*KGRound2Message1).OpenShare(session.Params[to.Index], from)
+					assert.Error(t, err)
+				}
+			}
+			test.RouteMessage(parties, msg, errCh)
+		case <-endCh:
+			ended++
+		}
+	}
+	assert.Equal(t, n*(n-1), shares)
+}
+
 func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	fixtureFileName := makeTestFixtureFilePath(index)
 
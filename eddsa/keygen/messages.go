@@ -8,8 +8,11 @@ package keygen
 
 import (
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
@@ -45,7 +48,7 @@ func NewKGRound1Message(from *tss.PartyID, ct cmt.HashCommitment) tss.ParsedMess
 }
 
 func (m *KGRound1Message) ValidateBasic() bool {
-	return m != nil && common.NonEmptyBytes(m.GetCommitment())
+	return m != nil && common.NonEmptyBytesMaxBits(m.GetCommitment(), MaxCommitmentBits())
 }
 
 func (m *KGRound1Message) UnmarshalCommitment() *big.Int {
@@ -70,15 +73,53 @@ func NewKGRound2Message1(
 	return tss.NewMessage(meta, content, msg)
 }
 
+// NewEncryptedKGRound2Message1 is NewKGRound2Message1 with the share encrypted to the identity key of `to`,
+// for parties that were given tss.Parameters.SetEncryptShares
+func NewEncryptedKGRound2Message1(
+	params *tss.Parameters,
+	to *tss.PartyID,
+	share *vss.Share,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:        params.PartyID(),
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &KGRound2Message1{}
+	encShare, err := params.SealShare(to, string(proto.MessageName(content)), share.Share.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	content.EncryptedShare = encShare
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
 func (m *KGRound2Message1) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *KGRound2Message1) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.GetShare())
+		ValidShareBytes(m.GetShare(), m.GetEncryptedShare(), ec)
 }
 
 func (m *KGRound2Message1) UnmarshalShare() *big.Int {
 	return new(big.Int).SetBytes(m.Share)
 }
 
+// OpenShare returns the share sent by `from`, decrypting it when it was encrypted to this party
+func (m *KGRound2Message1) OpenShare(params *tss.Parameters, from *tss.PartyID) (*big.Int, error) {
+	share, err := params.OpenShare(from, string(proto.MessageName(m)), m.GetShare(), m.GetEncryptedShare())
+	if err != nil {
+		return nil, err
+	}
+	if !common.NonEmptyBytesMaxBits(share, MaxScalarBits(params.EC())) {
+		return nil, errors.New("received an invalid share")
+	}
+	return new(big.Int).SetBytes(share), nil
+}
+
 // ----- //
 
 func NewKGRound2Message2(
@@ -102,8 +143,15 @@ func NewKGRound2Message2(
 }
 
 func (m *KGRound2Message2) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *KGRound2Message2) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.GetDeCommitment())
+		common.NonEmptyMultiBytesMaxBits(m.GetDeCommitment(), MaxDeCommitmentBits(ec)) &&
+		common.BytesMaxBits(m.GetProofAlphaX(), MaxScalarBits(ec)) &&
+		common.BytesMaxBits(m.GetProofAlphaY(), MaxScalarBits(ec)) &&
+		common.BytesMaxBits(m.GetProofT(), MaxScalarBits(ec))
 }
 
 func (m *KGRound2Message2) UnmarshalDeCommitment() []*big.Int {
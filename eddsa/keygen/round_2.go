@@ -41,7 +41,16 @@ func (round *round2) Start() *tss.Error {
 			round.temp.kgRound2Message1s[j] = r2msg1
 			continue
 		}
+		if round.EncryptShares() {
+			var err error
+			if r2msg1, err = NewEncryptedKGRound2Message1(round.Params(), Pj, shares[j]); err != nil {
+				return round.WrapError(err)
+			}
+		}
 		round.temp.kgRound2Message1s[i] = r2msg1
+		if err := round.StampMessage(r2msg1); err != nil {
+			return round.WrapError(err)
+		}
 		round.out <- r2msg1
 	}
 
@@ -55,6 +64,9 @@ func (round *round2) Start() *tss.Error {
 	// 5. BROADCAST de-commitments of Shamir poly*G and Schnorr prove
 	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG, pii)
 	round.temp.kgRound2Message2s[i] = r2msg2
+	if err := round.StampMessage(r2msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r2msg2
 
 	return nil
@@ -33,12 +33,17 @@ func (round *round3) Start() *tss.Error {
 
 	// 1,10. calculate xi
 	xi := new(big.Int).Set(round.temp.shares[PIdx].Share)
-	for j := range Ps {
+	shares := make([]*big.Int, len(Ps))
+	for j, Pj := range Ps {
 		if j == PIdx {
 			continue
 		}
 		r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
-		share := r2msg1.UnmarshalShare()
+		share, err := r2msg1.OpenShare(round.Params(), Pj)
+		if err != nil {
+			return round.WrapError(err, Pj)
+		}
+		shares[j] = share
 		xi = new(big.Int).Add(xi, share)
 	}
 	round.save.Xi = new(big.Int).Mod(xi, round.Params().EC().Params().N)
@@ -95,18 +100,20 @@ func (round *round3) Start() *tss.Error {
 				return
 			}
 			ok = proof.Verify(ContextJ, PjVs[0])
+			round.observeProof(Ps[j], tss.ProofSchnorr, ok)
 			if !ok {
-				ch <- vssOut{errors.New("failed to prove schnorr proof"), nil}
+				ch <- vssOut{tss.NewProofError(tss.ProofSchnorr, errors.New("failed to prove schnorr proof")), nil}
 				return
 			}
-			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
 			PjShare := vss.Share{
 				Threshold: round.Threshold(),
 				ID:        round.PartyID().KeyInt(),
-				Share:     r2msg1.UnmarshalShare(),
+				Share:     shares[j],
 			}
-			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
-				ch <- vssOut{errors.New("vss verify failed"), nil}
+			ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs)
+			round.observeProof(Ps[j], tss.ProofVSS, ok)
+			if !ok {
+				ch <- vssOut{tss.NewProofError(tss.ProofVSS, errors.New("vss verify failed")), nil}
 				return
 			}
 			// (9) handled above
@@ -193,8 +200,12 @@ func (round *round3) Start() *tss.Error {
 	round.save.EDDSAPub = eddsaPubKey
 
 	// PRINT public key & private share
-	common.Logger.Debugf("%s public key: %x", round.PartyID(), eddsaPubKey)
+	round.Logger().Debugf("%s public key: %x", round.PartyID(), eddsaPubKey)
 
+	// every party's share was verified above, so this round is complete
+	for j := range round.ok {
+		round.ok[j] = true
+	}
 	round.end <- round.save
 	return nil
 }
@@ -8,9 +8,12 @@ package keygen
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -41,6 +44,43 @@ func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
 	return
 }
 
+// ValidateShare checks that the save data is a consistent share of this party of a key of the parties of `params`:
+// Xi must be the secret of the public share of this party, and any threshold+1 public shares must interpolate to EDDSAPub.
+func (save LocalPartySaveData) ValidateShare(params *tss.Parameters) error {
+	if len(save.BigXj) != len(save.Ks) {
+		return errors.New("the save data does not hold the same number of values for every party")
+	}
+	if save.Xi == nil || save.ShareID == nil {
+		return errors.New("the save data has no secret share")
+	}
+	if save.EDDSAPub == nil || !save.EDDSAPub.ValidateBasic() {
+		return errors.New("the save data has no valid public key")
+	}
+
+	// the parties
+	if params.PartyID().KeyInt().Cmp(save.ShareID) != 0 {
+		return errors.New("the share belongs to another party")
+	}
+	i, err := vss.ShareIndex(params.EC(), save.Ks, params.Parties().IDs().Keys(), save.ShareID)
+	if err != nil {
+		return fmt.Errorf("the party keys do not match the parties: %w", err)
+	}
+
+	// the secret and public shares
+	for j, Xj := range save.BigXj {
+		if Xj == nil || !Xj.ValidateBasic() {
+			return fmt.Errorf("the public share of party %d is not valid", j)
+		}
+	}
+	if !crypto.ScalarBaseMult(params.EC(), save.Xi).Equals(save.BigXj[i]) {
+		return errors.New("the secret share does not match the public share of this party")
+	}
+	if err = vss.CheckPublicShares(params.EC(), params.Threshold(), save.Ks, save.BigXj, save.EDDSAPub); err != nil {
+		return fmt.Errorf("the public shares do not match the public key: %w", err)
+	}
+	return nil
+}
+
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
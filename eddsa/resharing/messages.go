@@ -8,12 +8,16 @@ package resharing
 
 import (
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -54,10 +58,14 @@ func NewDGRound1Message(
 }
 
 func (m *DGRound1Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound1Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.EddsaPubX) &&
-		common.NonEmptyBytes(m.EddsaPubY) &&
-		common.NonEmptyBytes(m.VCommitment)
+		common.NonEmptyBytesMaxBits(m.EddsaPubX, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.EddsaPubY, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.VCommitment, keygen.MaxCommitmentBits())
 }
 
 func (m *DGRound1Message) UnmarshalEDDSAPub(ec elliptic.Curve) (*crypto.ECPoint, error) {
@@ -112,9 +120,48 @@ func NewDGRound3Message1(
 	return tss.NewMessage(meta, content, msg)
 }
 
+// NewEncryptedDGRound3Message1 is NewDGRound3Message1 with the share encrypted to the identity key of `to`,
+// for parties that were given tss.Parameters.SetEncryptShares
+func NewEncryptedDGRound3Message1(
+	params *tss.Parameters,
+	to *tss.PartyID,
+	share *vss.Share,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:             params.PartyID(),
+		To:               []*tss.PartyID{to},
+		IsBroadcast:      false,
+		IsToOldCommittee: false,
+	}
+	content := &DGRound3Message1{}
+	encShare, err := params.SealShare(to, string(proto.MessageName(content)), share.Share.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	content.EncryptedShare = encShare
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
 func (m *DGRound3Message1) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound3Message1) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.Share)
+		keygen.ValidShareBytes(m.GetShare(), m.GetEncryptedShare(), ec)
+}
+
+// OpenShare returns the share sent by `from`, decrypting it when it was encrypted to this party
+func (m *DGRound3Message1) OpenShare(params *tss.Parameters, from *tss.PartyID) (*big.Int, error) {
+	share, err := params.OpenShare(from, string(proto.MessageName(m)), m.GetShare(), m.GetEncryptedShare())
+	if err != nil {
+		return nil, err
+	}
+	if !common.NonEmptyBytesMaxBits(share, keygen.MaxScalarBits(params.EC())) {
+		return nil, errors.New("received an invalid share")
+	}
+	return new(big.Int).SetBytes(share), nil
 }
 
 // ----- //
@@ -139,8 +186,12 @@ func NewDGRound3Message2(
 }
 
 func (m *DGRound3Message2) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *DGRound3Message2) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.VDecommitment)
+		common.NonEmptyMultiBytesMaxBits(m.VDecommitment, keygen.MaxDeCommitmentBits(ec))
 }
 
 func (m *DGRound3Message2) UnmarshalVDeCommitment() cmt.HashDeCommitment {
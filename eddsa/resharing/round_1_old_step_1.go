@@ -72,6 +72,9 @@ func (round *round1) Start() *tss.Error {
 		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
 		round.input.EDDSAPub, vCmt.C)
 	round.temp.dgRound1Messages[i] = r1msg
+	if err := round.StampMessage(r1msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r1msg
 
 	return nil
@@ -32,6 +32,9 @@ func (round *round2) Start() *tss.Error {
 	// 1. "broadcast" "ACK" members of the OLD committee
 	r2msg := NewDGRound2Message(round.OldParties().IDs(), Pi)
 	round.temp.dgRound2Messages[i] = r2msg
+	if err := round.StampMessage(r2msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r2msg
 
 	return nil
@@ -67,13 +67,19 @@ func (round *round4) Start() *tss.Error {
 		vjc[j] = vj
 
 		r3msg1 := round.temp.dgRound3Message1s[j].Content().(*DGRound3Message1)
+		share, err := r3msg1.OpenShare(round.Params(), round.Parties().IDs()[j])
+		if err != nil {
+			return round.WrapError(err, round.Parties().IDs()[j])
+		}
 		sharej := &vss.Share{
 			Threshold: round.NewThreshold(),
 			ID:        round.PartyID().KeyInt(),
-			Share:     new(big.Int).SetBytes(r3msg1.Share),
+			Share:     share,
 		}
-		if ok := sharej.Verify(round.Params().EC(), round.NewThreshold(), vj); !ok {
-			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
+		ok = sharej.Verify(round.Params().EC(), round.NewThreshold(), vj)
+		round.observeProof(round.Parties().IDs()[j], tss.ProofVSS, ok)
+		if !ok {
+			return round.WrapError(tss.NewProofError(tss.ProofVSS, errors.New("share from old committee did not pass Verify()")), round.Parties().IDs()[j])
 		}
 
 		newXi = new(big.Int).Add(newXi, sharej.Share)
@@ -127,6 +133,9 @@ func (round *round4) Start() *tss.Error {
 	// 21. Send an "ACK" message to both committees to signal that we're ready to save our data
 	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi)
 	round.temp.dgRound4Messages[i] = r4msg
+	if err := round.StampMessage(r4msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r4msg
 
 	return nil
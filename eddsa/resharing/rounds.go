@@ -108,6 +108,11 @@ func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
 }
 
+// observeProof reports the outcome of verifying a proof sent by Pj to the Observer on the Parameters
+func (round *base) observeProof(Pj *tss.PartyID, proof tss.ProofType, ok bool) {
+	tss.ObserveProof(round.Params(), TaskName, round.number, Pj, proof, ok)
+}
+
 // ----- //
 
 // `oldOK` tracks parties which have been verified by Update()
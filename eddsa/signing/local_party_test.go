@@ -12,6 +12,7 @@ import (
 	"math/big"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/agl/ed25519/edwards25519"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
@@ -21,6 +22,7 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/test/simulator"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -248,3 +250,35 @@ signing:
 		}
 	}
 }
+
+func TestE2EOverReorderingNetwork(t *testing.T) {
+	setUp("error")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	pk := edwards.PublicKey{Curve: tss.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	msg := big.NewInt(200)
+
+	// messages arrive late, out of order and twice
+	cfg := simulator.Config{Latency: 10 * time.Millisecond, Jitter: 50 * time.Millisecond, DuplicateRate: 0.2}
+	for seed := int64(1); seed <= 5; seed++ {
+		cfg.Seed = seed
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		stats, err := simulator.Simulate(cfg, len(signPIDs), func(i int, out chan<- tss.Message) tss.Party {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			return NewLocalParty(msg, params, keys[i], out, endCh)
+		})
+		if !assert.NoError(t, err, "seed %d", seed) {
+			continue
+		}
+		assert.Equal(t, stats.Sent+stats.Duplicated, stats.Delivered)
+		assert.Len(t, endCh, len(signPIDs))
+		for len(endCh) > 0 {
+			data := <-endCh
+			sig, err := edwards.ParseSignature(data.Signature)
+			assert.NoError(t, err)
+			assert.True(t, edwards.Verify(&pk, msg.Bytes(), sig.R, sig.S), "eddsa verify must pass")
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -48,7 +49,7 @@ func NewSignRound1Message(
 
 func (m *SignRound1Message) ValidateBasic() bool {
 	return m.Commitment != nil &&
-		common.NonEmptyBytes(m.GetCommitment())
+		common.NonEmptyBytesMaxBits(m.GetCommitment(), keygen.MaxCommitmentBits())
 }
 
 func (m *SignRound1Message) UnmarshalCommitment() *big.Int {
@@ -78,11 +79,15 @@ func NewSignRound2Message(
 }
 
 func (m *SignRound2Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound2Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
-		common.NonEmptyBytes(m.ProofAlphaX) &&
-		common.NonEmptyBytes(m.ProofAlphaY) &&
-		common.NonEmptyBytes(m.ProofT)
+		common.NonEmptyMultiBytesMaxBits(m.DeCommitment, keygen.MaxDeCommitmentBits(ec), 3) &&
+		common.NonEmptyBytesMaxBits(m.ProofAlphaX, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.ProofAlphaY, keygen.MaxScalarBits(ec)) &&
+		common.NonEmptyBytesMaxBits(m.ProofT, keygen.MaxScalarBits(ec))
 }
 
 func (m *SignRound2Message) UnmarshalDeCommitment() []*big.Int {
@@ -122,8 +127,12 @@ func NewSignRound3Message(
 }
 
 func (m *SignRound3Message) ValidateBasic() bool {
+	return m.ValidateCurve(nil)
+}
+
+func (m *SignRound3Message) ValidateCurve(ec elliptic.Curve) bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.S)
+		common.NonEmptyBytesMaxBits(m.S, keygen.MaxScalarBits(ec))
 }
 
 func (m *SignRound3Message) UnmarshalS() *big.Int {
@@ -9,7 +9,6 @@ package signing
 import (
 	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
@@ -34,7 +33,7 @@ func (round *round1) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	round.temp.ssidNonce = round.SSIDNonce()
 	var err error
 	round.temp.ssid, err = round.getSSID()
 	if err != nil {
@@ -58,6 +57,9 @@ func (round *round1) Start() *tss.Error {
 	// 4. broadcast commitment
 	r1msg2 := NewSignRound1Message(round.PartyID(), cmt.C)
 	round.temp.signRound1Messages[i] = r1msg2
+	if err := round.StampMessage(r1msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r1msg2
 
 	return nil
@@ -42,6 +42,9 @@ func (round *round2) Start() *tss.Error {
 	// 3. BROADCAST de-commitments of Shamir poly*G and Schnorr prove
 	r2msg2 := NewSignRound2Message(round.PartyID(), round.temp.deCommit, pir)
 	round.temp.signRound2Messages[i] = r2msg2
+	if err := round.StampMessage(r2msg2); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r2msg2
 
 	return nil
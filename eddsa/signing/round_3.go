@@ -62,8 +62,9 @@ func (round *round3) Start() *tss.Error {
 			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj)
 		}
 		ok = proof.Verify(ContextJ, Rj)
+		round.observeProof(Pj, tss.ProofSchnorr, ok)
 		if !ok {
-			return round.WrapError(errors.New("failed to prove Rj"), Pj)
+			return round.WrapError(tss.NewProofError(tss.ProofSchnorr, errors.New("failed to prove Rj")), Pj)
 		}
 
 		extendedRj := ecPointToExtendedElement(round.Params().EC(), Rj.X(), Rj.Y(), round.Rand())
@@ -104,6 +105,9 @@ func (round *round3) Start() *tss.Error {
 	// 10. broadcast si to other parties
 	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(&localS))
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
+	if err := round.StampMessage(r3msg); err != nil {
+		return round.WrapError(err)
+	}
 	round.out <- r3msg
 
 	return nil
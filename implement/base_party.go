@@ -1,11 +1,17 @@
 package implement
 
 import (
+	"context"
 	"crypto/elliptic"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -15,6 +21,11 @@ const (
 
 type Sender func(msg tss.Message)
 
+var errNoCurve = errors.New("the party has no curve, call SetCurve before Init or InitReshare")
+
+// PartyKeyFunc derives the key of the PartyID of a participant from its id
+type PartyKeyFunc func(ec elliptic.Curve, id string) *big.Int
+
 type BaseParty struct {
 	PartyID       *tss.PartyID
 	Params        *tss.Parameters
@@ -24,17 +35,22 @@ type BaseParty struct {
 	ErrChan       chan error
 	closeChan     chan struct{}
 	sender        Sender
+	transport     Transport
+	recorder      *tss.Recorder
+	keyStore      KeyStore
+	partyKey      PartyKeyFunc
 	curve         elliptic.Curve
 }
 
 func NewBaseParty(partyID string) *BaseParty {
 	moniker := fmt.Sprintf("%s:%s", partyID, "keygen")
 	return &BaseParty{
-		PartyID:   tss.NewPartyID(partyID, moniker, new(big.Int).SetBytes([]byte(partyID))),
+		PartyID:   tss.NewPartyID(partyID, moniker, LegacyPartyKey(nil, partyID)),
 		In:        make(chan tss.Message, defaultChanSize),
 		Out:       make(chan tss.Message, defaultChanSize),
 		ErrChan:   make(chan error, defaultChanSize),
 		closeChan: make(chan struct{}),
+		partyKey:  LegacyPartyKey,
 	}
 }
 
@@ -42,20 +58,110 @@ func (p *BaseParty) SetSender(sender Sender) {
 	p.sender = sender
 }
 
+// SetRecorder makes the party record the messages it sends and processes into a transcript, see tss.Replay
+func (p *BaseParty) SetRecorder(recorder *tss.Recorder) {
+	p.recorder = recorder
+}
+
+// SetKeyStore makes the party keep the key shares that keygen and resharing output in `keyStore`,
+// from which LoadShareData can set them again
+func (p *BaseParty) SetKeyStore(keyStore KeyStore) {
+	p.keyStore = keyStore
+}
+
+// StoreKey puts the save data of the key `pub` into the key store as JSON and returns its fingerprint.
+// It does nothing when no key store was set.
+func (p *BaseParty) StoreKey(pub *crypto.ECPoint, saveData interface{}) (string, error) {
+	if p.keyStore == nil {
+		return "", nil
+	}
+	bz, err := json.Marshal(saveData)
+	if err != nil {
+		return "", err
+	}
+	return p.keyStore.Put(pub, bz)
+}
+
+// LoadKey reads the save data stored under `fingerprint` from the key store
+func (p *BaseParty) LoadKey(fingerprint string) ([]byte, error) {
+	if p.keyStore == nil {
+		return nil, errors.New("the party has no key store")
+	}
+	return p.keyStore.Get(fingerprint)
+}
+
+// ShareParams returns the parameters that a key share set on the party must fit: those of Init, or else those of the
+// old committee of InitReshare
+func (p *BaseParty) ShareParams() (*tss.Parameters, error) {
+	switch {
+	case p.Params != nil:
+		return p.Params, nil
+	case p.ReshareParams != nil:
+		return p.ReshareParams.Parameters, nil
+	default:
+		return nil, errors.New("the party must be initialized before its key share is set")
+	}
+}
+
 func (p *BaseParty) SetCurve(curve elliptic.Curve) {
 	p.curve = curve
+	p.PartyID.Key = p.partyKey(curve, p.PartyID.Id).Bytes()
+}
+
+// SetPartyKeyFunc changes how the keys of the parties are derived from their ids, which is LegacyPartyKey by default.
+// It must be called before Init or InitReshare, with the same function on every party; new deployments should pass
+// PartyKey, while the key shares generated with one derivation can only be used with it.
+func (p *BaseParty) SetPartyKeyFunc(partyKey PartyKeyFunc) {
+	p.partyKey = partyKey
+	if p.curve != nil {
+		p.PartyID.Key = partyKey(p.curve, p.PartyID.Id).Bytes()
+	}
 }
 
 func (p *BaseParty) GetCurve() elliptic.Curve {
 	return p.curve
 }
 
-func CreateSortedPartyIDs(participants []string) tss.SortedPartyIDs {
+// PartyKey hashes `id` into a non-zero scalar below the order of `ec`, so that every party has a valid VSS index
+func PartyKey(ec elliptic.Curve, id string) *big.Int {
+	one := big.NewInt(1)
+	key := new(big.Int).SetBytes(common.SHA512_256([]byte("tss-lib party key"), []byte(id)))
+	key.Mod(key, new(big.Int).Sub(ec.Params().N, one))
+	return key.Add(key, one)
+}
+
+// LegacyPartyKey is the default key derivation, the bytes of the id itself, with which the existing key shares were
+// generated. Long ids give keys above the curve order, which are refused when two of them are equal modulo the order.
+func LegacyPartyKey(_ elliptic.Curve, id string) *big.Int {
+	return new(big.Int).SetBytes([]byte(id))
+}
+
+// CreateSortedPartyIDs creates the PartyIDs of the participants with the keys derived by LegacyPartyKey
+func CreateSortedPartyIDs(ec elliptic.Curve, participants []string) (tss.SortedPartyIDs, error) {
+	return CreateSortedPartyIDsWithKeys(ec, participants, LegacyPartyKey)
+}
+
+// CreateSortedPartyIDsWithKeys creates the PartyIDs of the participants with the keys derived by `partyKey`.
+// It fails if two participants have the same id, or if a key is zero or equal to another one modulo the order of `ec`.
+func CreateSortedPartyIDsWithKeys(ec elliptic.Curve, participants []string, partyKey PartyKeyFunc) (tss.SortedPartyIDs, error) {
 	partyIDs := make(tss.UnSortedPartyIDs, len(participants))
+	keys := make([]*big.Int, len(participants))
+	if ec == nil {
+		return nil, errors.New("cannot derive the party keys without a curve")
+	}
+	seen := make(map[string]struct{}, len(participants))
 	for i, participant := range participants {
-		partyIDs[i] = tss.NewPartyID(participant, fmt.Sprintf("%s:%s", participant, "keygen"), new(big.Int).SetBytes([]byte(participant)))
+		if _, ok := seen[participant]; ok {
+			return nil, fmt.Errorf("duplicate participant %s", participant)
+		}
+		seen[participant] = struct{}{}
+		keys[i] = partyKey(ec, participant)
+		partyIDs[i] = tss.NewPartyID(participant, fmt.Sprintf("%s:%s", participant, "keygen"), keys[i])
 	}
-	return tss.SortPartyIDs(partyIDs)
+	if _, err := vss.CheckIndexes(ec, keys); err != nil {
+		return nil, fmt.Errorf("the party keys are not valid VSS indexes: %w", err)
+	}
+	return tss.SortPartyIDs(partyIDs), nil
 }
 
 func GetLocalPartyIndex(partyIDs tss.SortedPartyIDs, partyID string) int {
@@ -80,7 +186,16 @@ func (p *BaseParty) SendMessages() {
 		case <-p.closeChan:
 			return
 		case msg := <-p.Out:
-			if p.sender != nil {
+			if p.recorder != nil {
+				if err := p.recorder.RecordOutbound(msg); err != nil {
+					p.ErrChan <- err
+				}
+			}
+			if p.transport != nil {
+				if err := p.transport.Send(p.Recipients(msg), msg); err != nil {
+					p.ErrChan <- err
+				}
+			} else if p.sender != nil {
 				p.sender(msg)
 			}
 		}
@@ -95,26 +210,49 @@ func (p *BaseParty) NotifyError() {
 
 func (p *BaseParty) Close() {
 	close(p.closeChan)
+	if p.transport != nil {
+		if err := p.transport.Close(); err != nil {
+			log.Printf("Party %s failed to close its transport: %v", p.PartyID.Id, err)
+		}
+	}
 	close(p.In)
 	close(p.Out)
 	close(p.ErrChan)
 }
 
-// Init initializes the party with basic parameters
-func (p *BaseParty) Init(participants []string, threshold int, sender Sender) {
-	sortedPartyIDs := CreateSortedPartyIDs(participants)
+// Init initializes the party with basic parameters.
+// It fails when the participants cannot be given distinct keys, before any protocol is run.
+func (p *BaseParty) Init(participants []string, threshold int, sender Sender) error {
+	if p.curve == nil {
+		return errNoCurve
+	}
+	sortedPartyIDs, err := CreateSortedPartyIDsWithKeys(p.curve, participants, p.partyKey)
+	if err != nil {
+		return err
+	}
 	// Update the partyID index
 	p.PartyID.Index = GetLocalPartyIndex(sortedPartyIDs, p.PartyID.Id)
 	ctx := tss.NewPeerContext(sortedPartyIDs)
 	p.Params = tss.NewParameters(p.curve, ctx, p.PartyID, len(participants), threshold)
+	p.setTransportParties(sortedPartyIDs)
 	p.SetSender(sender)
 	go p.SendMessages()
+	return nil
 }
 
-// InitReshare initializes the party for resharing
-func (p *BaseParty) InitReshare(oldParticipants []string, newParticipants []string, oldThreshold int, newThreshold int, sender Sender) {
-	oldSortedPartyIDs := CreateSortedPartyIDs(oldParticipants)
-	newSortedPartyIDs := CreateSortedPartyIDs(newParticipants)
+// InitReshare initializes the party for resharing; like Init, it fails when the participants cannot be given distinct keys
+func (p *BaseParty) InitReshare(oldParticipants []string, newParticipants []string, oldThreshold int, newThreshold int, sender Sender) error {
+	if p.curve == nil {
+		return errNoCurve
+	}
+	oldSortedPartyIDs, err := CreateSortedPartyIDsWithKeys(p.curve, oldParticipants, p.partyKey)
+	if err != nil {
+		return err
+	}
+	newSortedPartyIDs, err := CreateSortedPartyIDsWithKeys(p.curve, newParticipants, p.partyKey)
+	if err != nil {
+		return err
+	}
 
 	// Only update index for new parties
 	if p.PartyID.Index == -1 {
@@ -131,23 +269,92 @@ func (p *BaseParty) InitReshare(oldParticipants []string, newParticipants []stri
 		len(newParticipants),
 		newThreshold,
 	)
+	p.setTransportParties(p.ReshareParams.OldAndNewParties())
 	p.SetSender(sender)
 	go p.SendMessages()
+	return nil
 }
 
 // ProcessMsg handles message processing for any party implementation
 func (p *BaseParty) ProcessMsg(localParty tss.Party, msg tss.Message) error {
-	bz, _, err := msg.WireBytes()
+	parsed, err := p.parseMsg(msg)
 	if err != nil {
+		return localParty.WrapError(err)
+	}
+	if ok, err := localParty.Update(parsed); !ok {
 		return err
 	}
-	ok, err := localParty.UpdateFromBytes(bz, msg.GetFrom(), msg.IsBroadcast())
-	if !ok {
+	return nil
+}
+
+// Run starts `localParty` and passes it the messages received on In until it has no more rounds.
+// It returns the first fatal error: a failed start, an abort, or a failed round. Messages that are rejected without
+// failing the round are reported to ErrChan. The result of the protocol is then waiting in the end channel of the party.
+func (p *BaseParty) Run(ctx context.Context, localParty tss.Party) error {
+	if err := localParty.StartWithContext(ctx); err != nil {
 		return err
 	}
+	aborted := localParty.Aborted()
+	for localParty.Running() {
+		select {
+		case err := <-aborted:
+			return err
+		case msg, ok := <-p.In:
+			if !ok {
+				return errors.New("the party was closed")
+			}
+			parsed, err := p.parseMsg(msg)
+			if err != nil {
+				p.ReportError(localParty.WrapError(err))
+				continue
+			}
+			if _, err := localParty.Update(parsed); err != nil {
+				if isFatal(err) {
+					return err
+				}
+				p.ReportError(err)
+			}
+		}
+	}
 	return nil
 }
 
+func (p *BaseParty) parseMsg(msg tss.Message) (tss.ParsedMessage, error) {
+	bz, routing, err := msg.WireBytes()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := tss.ParseWireMessageWithRouting(bz, routing)
+	if err != nil {
+		return nil, err
+	}
+	if p.recorder != nil {
+		if err := p.recorder.RecordInbound(parsed); err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// ReportError sends err to ErrChan, unless the party was closed or ErrChan is full
+func (p *BaseParty) ReportError(err error) {
+	select {
+	case <-p.closeChan:
+		return
+	default:
+	}
+	select {
+	case p.ErrChan <- err:
+	default:
+		log.Printf("Party %s dropped error: %v", p.PartyID.Id, err)
+	}
+}
+
+// isFatal is true for an error of Update that failed the round, rather than only rejecting the message
+func isFatal(err *tss.Error) bool {
+	return !errors.Is(err.Cause(), tss.ErrInvalidMessage)
+}
+
 // HashToInt converts a hash to a big integer, respecting the curve's order
 func (p *BaseParty) HashToInt(hash []byte) *big.Int {
 	if p.curve == nil {
@@ -1,9 +1,12 @@
 package ecdsa
 
 import (
+	"context"
 	"crypto/ecdsa"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
 	"sync"
 	"testing"
@@ -12,12 +15,21 @@ import (
 	_ "net/http/pprof"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ckd"
 	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
 	"github.com/bnb-chain/tss-lib/v2/implement"
+	"github.com/bnb-chain/tss-lib/v2/test/simulator"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
+// relay delivers the messages of the test parties late, out of order and twice
+var relay = simulator.NewRelay(simulator.Config{Seed: 1, Latency: time.Millisecond, Jitter: 5 * time.Millisecond, DuplicateRate: 0.2})
+
 // testConfig holds configuration for tests
 type testConfig struct {
 	threshold     int
@@ -50,20 +62,111 @@ func setupTestParties(t *testing.T, cfg testConfig) []*ECDSAParty {
 	// Initialize parties with senders
 	senders := senders(parties)
 	for i, party := range parties {
-		party.Init(cfg.participants, cfg.threshold, *preParams[i], senders[i])
+		require.NoError(t, party.Init(cfg.participants, cfg.threshold, *preParams[i], senders[i]))
 		go party.NotifyError()
 	}
 
 	return parties
 }
 
+// endSession delivers the messages the relay still holds and drops those nobody read, once every party has finished
+// a session. A late duplicate of a finished session would otherwise be read by the next one.
+func endSession(parties []*ECDSAParty) {
+	relay.Flush()
+	for _, party := range parties {
+		for len(party.In) > 0 {
+			<-party.In
+		}
+	}
+}
+
 // cleanupTestParties ensures proper cleanup of test resources
 func cleanupTestParties(parties []*ECDSAParty) {
+	relay.Flush()
 	for _, party := range parties {
 		party.Close()
 	}
 }
 
+func TestClassifyMessage(t *testing.T) {
+	cases := []struct {
+		msg       proto.Message
+		protocol  tss.Protocol
+		round     int
+		routing   tss.Routing
+		committee tss.Committee
+	}{
+		{&keygen.KGRound2Message1{}, tss.ProtocolKeygen, 2, tss.Routing_P2P, tss.Committee_ALL_PARTIES},
+		{&keygen.KGRound3Message{}, tss.ProtocolKeygen, 3, tss.Routing_BROADCAST, tss.Committee_ALL_PARTIES},
+		{&signing.SignRound1Message1{}, tss.ProtocolSigning, 1, tss.Routing_P2P, tss.Committee_ALL_PARTIES},
+		{&signing.SignRound9Message{}, tss.ProtocolSigning, 9, tss.Routing_BROADCAST, tss.Committee_ALL_PARTIES},
+		{&signing.SignBatchMessage{}, tss.ProtocolSigning, 0, tss.Routing_BROADCAST_OR_P2P, tss.Committee_ALL_PARTIES},
+		{&resharing.DGRound2Message2{}, tss.ProtocolResharing, 2, tss.Routing_BROADCAST, tss.Committee_OLD_COMMITTEE},
+		{&resharing.DGRound4Message1{}, tss.ProtocolResharing, 4, tss.Routing_P2P, tss.Committee_NEW_COMMITTEE},
+	}
+	for _, c := range cases {
+		class, err := tss.ClassifyTypeURL("type.googleapis.com/" + string(proto.MessageName(c.msg)))
+		require.NoError(t, err)
+		require.Equal(t, tss.FamilyECDSA, class.Family, class.String())
+		require.Equal(t, c.protocol, class.Protocol, class.String())
+		require.Equal(t, c.round, class.Round, class.String())
+		require.Equal(t, c.routing, class.Routing, class.String())
+		require.Equal(t, c.committee, class.Committee, class.String())
+		require.Equal(t, c.routing == tss.Routing_BROADCAST, class.IsBroadcast())
+	}
+
+	// a batch is broadcast or not as told by its routing
+	pIDs := tss.GenerateTestPartyIDs(2)
+	for _, isBroadcast := range []bool{true, false} {
+		var to []*tss.PartyID
+		if !isBroadcast {
+			to = pIDs[1:]
+		}
+		msg := signing.NewSignBatchMessage(to, pIDs[0], isBroadcast, [][]byte{{1}})
+		wireBytes, routing, err := msg.WireBytes()
+		require.NoError(t, err)
+		class, err := tss.ClassifyMessage(wireBytes)
+		require.NoError(t, err)
+		require.Equal(t, isBroadcast, class.IsBroadcastWith(routing))
+
+		// the deprecated ClassifyMsg can only tell the messages that are always broadcast
+		round, broadcast, err := ClassifyMsg(wireBytes)
+		require.NoError(t, err)
+		require.Equal(t, uint8(0), round)
+		require.False(t, broadcast)
+	}
+}
+
+func TestClassifyMsg(t *testing.T) {
+	// the deprecated ClassifyMsg numbers the rounds as it always has
+	cases := []struct {
+		msg         proto.Message
+		round       uint8
+		isBroadcast bool
+	}{
+		{&keygen.KGRound1Message{}, 1, true},
+		{&keygen.KGRound2Message1{}, 2, false},
+		{&keygen.KGRound3Message{}, 4, true},
+		{&signing.SignRound1Message1{}, 1, false},
+		{&signing.SignRound1Message2{}, 2, true},
+		{&signing.SignRound9Message{}, 10, true},
+		{&resharing.DGRound1Message{}, 11, true},
+		{&resharing.DGRound3Message2{}, 15, false},
+		{&resharing.DGRound4Message1{}, 16, false},
+		{&signing.SignBatchMessage{}, 0, false},
+	}
+	for _, c := range cases {
+		wireBytes, err := proto.Marshal(&anypb.Any{TypeUrl: "type.googleapis.com/" + string(proto.MessageName(c.msg))})
+		require.NoError(t, err)
+		round, isBroadcast, err := ClassifyMsg(wireBytes)
+		require.NoError(t, err)
+		require.Equal(t, c.round, round, proto.MessageName(c.msg))
+		require.Equal(t, c.isBroadcast, isBroadcast, proto.MessageName(c.msg))
+	}
+	_, _, err := ClassifyMsg([]byte{0xff})
+	require.Error(t, err)
+}
+
 func TestECDSAPartyKeygen2Once(t *testing.T) {
 	// go func() {
 	// 	clog.Println(http.ListenAndServe("localhost:6060", nil))
@@ -153,6 +256,50 @@ func TestECDSAPartyKeygen2Once(t *testing.T) {
 	fmt.Printf("\n=== All %d Key Generation Runs Completed ===\n", totalRuns)
 }
 
+func TestECDSAPartySignWithPath(t *testing.T) {
+	cfg := defaultTestConfig()
+	parties := setupTestParties(t, cfg)
+	defer cleanupTestParties(parties)
+
+	shares := keygenAll(parties)
+	require.Len(t, shares, len(parties))
+	for _, party := range parties {
+		require.NoError(t, party.SetShareData(shares[party.PartyID.Id]))
+	}
+
+	path, err := ckd.ParsePath("m/44/60/0/0/5")
+	require.NoError(t, err)
+	chainCode := make([]byte, 32)
+	_, err = rand.Read(chainCode)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	sigs := make([]*common.SignatureData, len(parties))
+	pubs := make([]*ecdsa.PublicKey, len(parties))
+	errs := make([]error, len(parties))
+	var wg sync.WaitGroup
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *ECDSAParty) {
+			defer wg.Done()
+			sigs[i], pubs[i], errs[i] = p.SignWithPath(ctx, path, chainCode, cfg.messageToSign)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+
+	var master keygen.LocalPartySaveData
+	require.NoError(t, json.Unmarshal(shares[parties[0].PartyID.Id], &master))
+	for i := range parties {
+		require.NoError(t, errs[i])
+		require.True(t, pubs[0].Equal(pubs[i]))
+	}
+	require.False(t, pubs[0].X.Cmp(master.ECDSAPub.X()) == 0, "the child key differs from the master key")
+	r, s := new(big.Int).SetBytes(sigs[0].R), new(big.Int).SetBytes(sigs[0].S)
+	require.True(t, ecdsa.Verify(pubs[0], parties[0].HashToInt(cfg.messageToSign).Bytes(), r, s))
+}
+
 func testResharing(t *testing.T, oldParties []*ECDSAParty, cfg testConfig) []*ECDSAParty {
 	// Create new parties for resharing
 	newParticipants := []string{"party1-reshare", "party2-reshare", "party3-reshare"}
@@ -171,14 +318,14 @@ func testResharing(t *testing.T, oldParties []*ECDSAParty, cfg testConfig) []*EC
 		preParams, err := loadPreparams(party.PartyID.Id)
 		require.NoError(t, err, "Failed to load pre-params for %s", party.PartyID.Id)
 
-		party.InitReshare(
+		require.NoError(t, party.InitReshare(
 			cfg.participants,
 			newParticipants,
 			cfg.threshold,
 			1, // new threshold
 			*preParams,
 			reshareSenders[i],
-		)
+		))
 		go party.NotifyError()
 	}
 
@@ -196,8 +343,8 @@ func testResharing(t *testing.T, oldParties []*ECDSAParty, cfg testConfig) []*EC
 		preParams, err := loadPreparams(party.PartyID.Id)
 		require.NoError(t, err, "Failed to load pre-params for %s", party.PartyID.Id)
 
-		party.Init(newParticipants, 1, *preParams, newSignSenders[i])
-		party.SetShareData(reshareShares[party.PartyID.Id])
+		require.NoError(t, party.Init(newParticipants, 1, *preParams, newSignSenders[i]))
+		require.NoError(t, party.SetShareData(reshareShares[party.PartyID.Id]))
 	}
 
 	// Test signing with new parties
@@ -236,6 +383,7 @@ func keygenAll(parties []*ECDSAParty) map[string][]byte {
 		}(party)
 	}
 	wg.Wait()
+	endSession(parties)
 	return shares
 }
 
@@ -267,6 +415,7 @@ func signAll(parties []*ECDSAParty, msg []byte) [][]byte {
 		}(party)
 	}
 	wg.Wait()
+	endSession(parties)
 	return sigs
 }
 
@@ -298,6 +447,7 @@ func reshareAll(parties []*ECDSAParty) map[string][]byte {
 		}(party)
 	}
 	wg.Wait()
+	endSession(parties)
 	return shares
 }
 
@@ -306,21 +456,21 @@ func senders(parties []*ECDSAParty) []implement.Sender {
 	for i, src := range parties {
 		src := src
 		senders[i] = func(msg tss.Message) {
-			msgBytes, _, err := msg.WireBytes()
+			msgBytes, routing, err := msg.WireBytes()
 			if err != nil {
 				common.Logger.Errorf("Party %s failed to get wire bytes: %v", src.PartyID.Id, err)
 				return
 			}
-			round, isBroadcast, err := ClassifyMsg(msgBytes)
+			class, err := tss.ClassifyMessage(msgBytes)
 			if err != nil {
 				common.Logger.Errorf("Party %s failed to classify message: %v", src.PartyID.Id, err)
 				return
 			}
-			common.Logger.Infof("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, round, isBroadcast)
-			if isBroadcast {
+			common.Logger.Infof("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, class.Round, class.IsBroadcastWith(routing))
+			if class.IsBroadcastWith(routing) {
 				for _, dst := range parties {
 					if dst.PartyID.Id != src.PartyID.Id {
-						dst.OnMsg(msg)
+						relay.Deliver(func() { dst.OnMsg(msg) })
 					}
 				}
 			} else {
@@ -332,7 +482,7 @@ func senders(parties []*ECDSAParty) []implement.Sender {
 				for _, recipient := range to {
 					for _, dst := range parties {
 						if recipient.Id == dst.PartyID.Id {
-							dst.OnMsg(msg)
+							relay.Deliver(func() { dst.OnMsg(msg) })
 							break
 						}
 					}
@@ -353,12 +503,12 @@ func senderForReshare(parties []*ECDSAParty) []implement.Sender {
 				common.Logger.Errorf("Party %s failed to get wire bytes: %v", src.PartyID.Id, err)
 				return
 			}
-			round, isBroadcast, err := ClassifyMsg(msgBytes)
+			class, err := tss.ClassifyMessage(msgBytes)
 			if err != nil {
 				common.Logger.Errorf("Party %s failed to classify message: %v", src.PartyID.Id, err)
 				return
 			}
-			common.Logger.Infof("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, round, isBroadcast)
+			common.Logger.Infof("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, class.Round, class.IsBroadcast())
 
 			to := msg.GetTo()
 			if to == nil {
@@ -368,7 +518,7 @@ func senderForReshare(parties []*ECDSAParty) []implement.Sender {
 			for _, recipient := range to {
 				for _, dst := range parties {
 					if recipient.Id == dst.PartyID.Id {
-						dst.OnMsg(msg)
+						relay.Deliver(func() { dst.OnMsg(msg) })
 						break
 					}
 				}
@@ -0,0 +1,73 @@
+package ecdsa
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/implement"
+)
+
+func TestECDSAPartyKeyStore(t *testing.T) {
+	cfg := defaultTestConfig()
+	parties := setupTestParties(t, cfg)
+	defer cleanupTestParties(parties)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	stores := make([]*implement.FileKeyStore, len(parties))
+	for i, party := range parties {
+		ks, err := implement.NewFileKeyStore(t.TempDir(), []byte("passphrase"))
+		require.NoError(t, err)
+		stores[i] = ks
+		party.SetKeyStore(ks)
+	}
+	shares := make([]*keygen.LocalPartySaveData, len(parties))
+	errs := make([]error, len(parties))
+	var wg sync.WaitGroup
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *ECDSAParty) {
+			defer wg.Done()
+			shares[i], errs[i] = p.RunKeygen(ctx)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+
+	// every party finds its share by the fingerprint of the public key, and signs with it
+	fingerprint := implement.KeyFingerprint(shares[0].ECDSAPub)
+	for i, party := range parties {
+		require.NoError(t, errs[i])
+		fingerprints, err := stores[i].List()
+		require.NoError(t, err)
+		require.Equal(t, []string{fingerprint}, fingerprints)
+		require.NoError(t, party.LoadShareData(fingerprint))
+	}
+	sigs := make([]*common.SignatureData, len(parties))
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *ECDSAParty) {
+			defer wg.Done()
+			sigs[i], errs[i] = p.RunSign(ctx, cfg.messageToSign)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+	pk := &ecdsa.PublicKey{Curve: parties[0].GetCurve(), X: shares[0].ECDSAPub.X(), Y: shares[0].ECDSAPub.Y()}
+	for i := range parties {
+		require.NoError(t, errs[i])
+		require.Equal(t, sigs[0].Signature, sigs[i].Signature)
+	}
+	r, s := new(big.Int).SetBytes(sigs[0].R), new(big.Int).SetBytes(sigs[0].S)
+	require.True(t, ecdsa.Verify(pk, parties[0].HashToInt(cfg.messageToSign).Bytes(), r, s))
+
+	require.ErrorIs(t, parties[0].LoadShareData("00112233445566778899aabbccddeeff"), implement.ErrKeyNotFound)
+}
@@ -1,8 +1,14 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
 package ecdsa
 
 import (
-	"github.com/golang/protobuf/ptypes/any"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 var (
@@ -60,8 +66,14 @@ var (
 	}
 )
 
+// ClassifyMsg returns the round of the message in `msgBytes` and whether it is always broadcast. The rounds are numbered
+// as this function always has, which is not as tss.ClassifyMessage does, and a message of a type it does not know is
+// round 0 and not broadcast.
+//
+// Deprecated: use tss.ClassifyMessage, which returns the protocol, round, routing and committee of every message of the
+// library.
 func ClassifyMsg(msgBytes []byte) (uint8, bool, error) {
-	msg := &any.Any{}
+	msg := new(anypb.Any)
 	if err := proto.Unmarshal(msgBytes, msg); err != nil {
 		return 0, false, err
 	}
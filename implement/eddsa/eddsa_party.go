@@ -1,7 +1,9 @@
 package eddsa
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -26,81 +28,115 @@ func NewEDDSAParty(partyID string) *EDDSAParty {
 	return party
 }
 
-func (p *EDDSAParty) Init(participants []string, threshold int, sender implement.Sender) {
-	p.BaseParty.Init(participants, threshold, sender)
+func (p *EDDSAParty) Init(participants []string, threshold int, sender implement.Sender) error {
+	return p.BaseParty.Init(participants, threshold, sender)
 }
 
-func (p *EDDSAParty) InitReshare(oldParticipants []string, newParticipants []string, oldThreshold int, newThreshold int, sender implement.Sender) {
-	p.BaseParty.InitReshare(oldParticipants, newParticipants, oldThreshold, newThreshold, sender)
+func (p *EDDSAParty) InitReshare(oldParticipants []string, newParticipants []string, oldThreshold int, newThreshold int, sender implement.Sender) error {
+	return p.BaseParty.InitReshare(oldParticipants, newParticipants, oldThreshold, newThreshold, sender)
 }
 
 func (p *EDDSAParty) Keygen(done func(*keygen.LocalPartySaveData)) {
+	p.KeygenWithContext(context.Background(), done)
+}
+
+// KeygenWithContext is like Keygen, but stops when ctx is done or a round exceeds the round timeout of the parameters.
+// Errors are reported on ErrChan. `done` is still called with the share when only putting it into the key store
+// failed, after that error was reported, as the share exists nowhere else then and must be kept by the caller.
+func (p *EDDSAParty) KeygenWithContext(ctx context.Context, done func(*keygen.LocalPartySaveData)) {
+	share, err := p.RunKeygen(ctx)
+	if err != nil {
+		p.ReportError(err)
+	}
+	if share != nil && done != nil {
+		done(share)
+	}
+}
+
+// RunKeygen runs keygen until it completes, and returns the key share of this party or the first fatal error.
+// The share is put into the key store, if one was set; when that fails, the share is returned along with the error.
+func (p *EDDSAParty) RunKeygen(ctx context.Context) (*keygen.LocalPartySaveData, error) {
 	log.Printf("Party %s starting keygen\n", p.PartyID.Id)
 	defer log.Printf("Party %s ending keygen\n", p.PartyID.Id)
 
+	if p.Params == nil {
+		return nil, errors.New("the party was not initialized with Init")
+	}
 	endCh := make(chan *keygen.LocalPartySaveData, 1)
 	localParty := keygen.NewLocalParty(p.Params, p.Out, endCh)
-
-	go func() {
-		if err := localParty.Start(); err != nil {
-			p.ErrChan <- err
-		}
-	}()
-
-	for {
-		select {
-		case share := <-endCh:
-			if done != nil {
-				done(share)
-			}
-			return
-		case msg := <-p.In:
-			if err := p.ProcessMsg(localParty, msg); err != nil {
-				p.ErrChan <- err
-			}
-		}
+	if err := p.Run(ctx, localParty); err != nil {
+		return nil, err
+	}
+	share := <-endCh
+	if _, err := p.StoreKey(share.EDDSAPub, share); err != nil {
+		return share, fmt.Errorf("failed storing the key share: %w", err)
 	}
+	return share, nil
 }
 
 func (p *EDDSAParty) Sign(msg []byte, done func(*common.SignatureData)) {
+	p.SignWithContext(context.Background(), msg, done)
+}
+
+// SignWithContext is like Sign, but stops when ctx is done or a round exceeds the round timeout of the parameters
+func (p *EDDSAParty) SignWithContext(ctx context.Context, msg []byte, done func(*common.SignatureData)) {
+	sig, err := p.RunSign(ctx, msg)
+	if err != nil {
+		p.ReportError(err)
+		return
+	}
+	if done != nil {
+		done(sig)
+	}
+}
+
+// RunSign signs msg with the key share set by SetShareData, and returns the signature or the first fatal error
+func (p *EDDSAParty) RunSign(ctx context.Context, msg []byte) (*common.SignatureData, error) {
 	log.Printf("Party %s starting sign\n", p.PartyID.Id)
 	defer log.Printf("Party %s ending sign\n", p.PartyID.Id)
 
+	if p.Params == nil {
+		return nil, errors.New("the party was not initialized with Init")
+	}
 	if p.shareData == nil {
-		log.Printf("Party %s has no share data", p.PartyID.Id)
-		return
+		return nil, fmt.Errorf("party %s has no share data", p.PartyID.Id)
 	}
-
 	endCh := make(chan *common.SignatureData, 1)
 	msgToSign := p.HashToInt(msg)
 	localParty := signing.NewLocalParty(msgToSign, p.Params, *p.shareData, p.Out, endCh)
-
-	go func() {
-		if err := localParty.Start(); err != nil {
-			log.Printf("Party %s failed to start: %v\n", p.PartyID.Id, err)
-			panic(err)
-		}
-	}()
-
-	for {
-		select {
-		case sig := <-endCh:
-			if done != nil {
-				done(sig)
-			}
-			return
-		case msg := <-p.In:
-			if err := p.ProcessMsg(localParty, msg); err != nil {
-				p.ErrChan <- err
-			}
-		}
+	if err := p.Run(ctx, localParty); err != nil {
+		return nil, err
 	}
+	return <-endCh, nil
 }
 
 func (p *EDDSAParty) Reshare(done func(*keygen.LocalPartySaveData)) {
+	p.ReshareWithContext(context.Background(), done)
+}
+
+// ReshareWithContext is like Reshare, but stops when ctx is done or a round exceeds the round timeout of the parameters.
+// Errors are reported on ErrChan. `done` is still called with the share when only putting it into the key store
+// failed, after that error was reported, as the share exists nowhere else then and must be kept by the caller.
+func (p *EDDSAParty) ReshareWithContext(ctx context.Context, done func(*keygen.LocalPartySaveData)) {
+	share, err := p.RunReshare(ctx)
+	if err != nil {
+		p.ReportError(err)
+	}
+	if share != nil && done != nil {
+		done(share)
+	}
+}
+
+// RunReshare runs resharing until it completes, and returns the new key share of this party or the first fatal error.
+// A party of the old committee only gets back a share with its secret cleared. The new share of a party of the new
+// committee is put into the key store like in RunKeygen.
+func (p *EDDSAParty) RunReshare(ctx context.Context) (*keygen.LocalPartySaveData, error) {
 	log.Printf("Party %s starting reshare\n", p.PartyID.Id)
 	defer log.Printf("Party %s ending reshare\n", p.PartyID.Id)
 
+	if p.ReshareParams == nil {
+		return nil, errors.New("the party was not initialized with InitReshare")
+	}
 	// Initialize share data for new participants
 	if p.shareData == nil {
 		data := keygen.NewLocalPartySaveData(p.ReshareParams.NewPartyCount())
@@ -109,51 +145,71 @@ func (p *EDDSAParty) Reshare(done func(*keygen.LocalPartySaveData)) {
 
 	endCh := make(chan *keygen.LocalPartySaveData, 1)
 	localParty := resharing.NewLocalParty(p.ReshareParams, *p.shareData, p.Out, endCh)
-
-	go func() {
-		if err := localParty.Start(); err != nil {
-			p.ErrChan <- err
-		}
-	}()
-
-	for {
-		select {
-		case share := <-endCh:
-			if done != nil {
-				done(share)
-			}
-			return
-		case msg := <-p.In:
-			if err := p.ProcessMsg(localParty, msg); err != nil {
-				p.ErrChan <- err
-			}
+	if err := p.Run(ctx, localParty); err != nil {
+		return nil, err
+	}
+	share := <-endCh
+	// the old committee has no share left to store
+	if p.ReshareParams.IsNewCommittee() {
+		if _, err := p.StoreKey(share.EDDSAPub, share); err != nil {
+			return share, fmt.Errorf("failed storing the key share: %w", err)
 		}
 	}
+	return share, nil
 }
 
-func (p *EDDSAParty) SetShareData(shareData []byte) {
-	var localSaveData keygen.LocalPartySaveData
-	err := json.Unmarshal(shareData, &localSaveData)
+// SetShareData sets the key share of the party from its JSON save data. The party must be initialized first: the share
+// is refused unless it is a consistent share of this party of a key of its parties, see keygen.LocalPartySaveData.ValidateShare.
+func (p *EDDSAParty) SetShareData(shareData []byte) error {
+	localSaveData, err := p.parseShareData(shareData)
+	if err != nil {
+		return err
+	}
+	p.shareData = localSaveData
+	return nil
+}
+
+// LoadShareData sets the key share stored under `fingerprint` in the key store, see SetKeyStore and implement.KeyFingerprint
+func (p *EDDSAParty) LoadShareData(fingerprint string) error {
+	shareData, err := p.LoadKey(fingerprint)
 	if err != nil {
-		p.ErrChan <- fmt.Errorf("failed deserializing shares: %w", err)
+		return err
 	}
+	localSaveData, err := p.parseShareData(shareData)
+	if err != nil {
+		return err
+	}
+	if implement.KeyFingerprint(localSaveData.EDDSAPub) != fingerprint {
+		return fmt.Errorf("the key stored under %s has another public key", fingerprint)
+	}
+	p.shareData = localSaveData
+	return nil
+}
 
-	// Validate share data
-	if localSaveData.EDDSAPub == nil {
-		p.ErrChan <- fmt.Errorf("share data has nil public key")
+func (p *EDDSAParty) parseShareData(shareData []byte) (*keygen.LocalPartySaveData, error) {
+	params, err := p.ShareParams()
+	if err != nil {
+		return nil, err
+	}
+	var localSaveData keygen.LocalPartySaveData
+	if err = json.Unmarshal(shareData, &localSaveData); err != nil {
+		return nil, fmt.Errorf("failed deserializing shares: %w", err)
 	}
-	if localSaveData.Xi == nil {
-		p.ErrChan <- fmt.Errorf("share data has nil private share")
+	if localSaveData.EDDSAPub == nil {
+		return nil, fmt.Errorf("share data has nil public key")
 	}
 
 	// Set curve for all points
-	localSaveData.EDDSAPub.SetCurve(p.GetCurve())
+	localSaveData.EDDSAPub.SetCurve(params.EC())
 	for _, xj := range localSaveData.BigXj {
 		if xj == nil {
-			p.ErrChan <- fmt.Errorf("share data has nil public share")
+			return nil, fmt.Errorf("share data has nil public share")
 		}
-		xj.SetCurve(p.GetCurve())
+		xj.SetCurve(params.EC())
 	}
 
-	p.shareData = &localSaveData
+	if err = localSaveData.ValidateShare(params); err != nil {
+		return nil, fmt.Errorf("invalid share data: %w", err)
+	}
+	return &localSaveData, nil
 }
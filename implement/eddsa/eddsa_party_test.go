@@ -1,21 +1,33 @@
 package eddsa
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/big"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/signing"
 	"github.com/bnb-chain/tss-lib/v2/implement"
+	"github.com/bnb-chain/tss-lib/v2/test/simulator"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
+// relay delivers the messages of the test parties late, out of order and twice
+var relay = simulator.NewRelay(simulator.Config{Seed: 1, Latency: time.Millisecond, Jitter: 5 * time.Millisecond, DuplicateRate: 0.2})
+
 // testConfig holds configuration for tests
 type testConfig struct {
 	threshold     int
@@ -44,15 +56,27 @@ func setupTestParties(t *testing.T, cfg testConfig) []*EDDSAParty {
 	// Initialize parties with senders
 	senders := senders(parties)
 	for i, party := range parties {
-		party.Init(cfg.participants, cfg.threshold, senders[i])
+		require.NoError(t, party.Init(cfg.participants, cfg.threshold, senders[i]))
 		go party.NotifyError()
 	}
 
 	return parties
 }
 
+// endSession delivers the messages the relay still holds and drops those nobody read, once every party has finished
+// a session. A late duplicate of a finished session would otherwise be read by the next one.
+func endSession(parties []*EDDSAParty) {
+	relay.Flush()
+	for _, party := range parties {
+		for len(party.In) > 0 {
+			<-party.In
+		}
+	}
+}
+
 // cleanupTestParties ensures proper cleanup of test resources
 func cleanupTestParties(parties []*EDDSAParty) {
+	relay.Flush()
 	for _, party := range parties {
 		party.Close()
 	}
@@ -72,7 +96,7 @@ func TestEDDSAParty(t *testing.T) {
 
 	// Set share data for each party
 	for _, party := range parties {
-		party.SetShareData(shares[party.PartyID.Id])
+		require.NoError(t, party.SetShareData(shares[party.PartyID.Id]))
 	}
 
 	// Test signing
@@ -100,13 +124,13 @@ func testResharing(t *testing.T, oldParties []*EDDSAParty, cfg testConfig) []*ED
 
 	// Initialize resharing for all parties
 	for i, party := range allParties {
-		party.InitReshare(
+		require.NoError(t, party.InitReshare(
 			cfg.participants,
 			newParticipants,
 			cfg.threshold,
 			1, // new threshold
 			reshareSenders[i],
-		)
+		))
 		go party.NotifyError()
 	}
 
@@ -121,8 +145,8 @@ func testResharing(t *testing.T, oldParties []*EDDSAParty, cfg testConfig) []*ED
 	// Initialize new parties for signing
 	newSignSenders := senders(newParties)
 	for i, party := range newParties {
-		party.Init(newParticipants, 1, newSignSenders[i])
-		party.SetShareData(reshareShares[party.PartyID.Id])
+		require.NoError(t, party.Init(newParticipants, 1, newSignSenders[i]))
+		require.NoError(t, party.SetShareData(reshareShares[party.PartyID.Id]))
 	}
 
 	// Test signing with new parties
@@ -161,6 +185,7 @@ func keygenAll(parties []*EDDSAParty) map[string][]byte {
 		}(party)
 	}
 	wg.Wait()
+	endSession(parties)
 	return shares
 }
 
@@ -192,6 +217,7 @@ func signAll(parties []*EDDSAParty, msg []byte) [][]byte {
 		}(party)
 	}
 	wg.Wait()
+	endSession(parties)
 	return sigs
 }
 
@@ -223,6 +249,7 @@ func reshareAll(parties []*EDDSAParty) map[string][]byte {
 		}(party)
 	}
 	wg.Wait()
+	endSession(parties)
 	return shares
 }
 
@@ -231,21 +258,21 @@ func senders(parties []*EDDSAParty) []implement.Sender {
 	for i, src := range parties {
 		src := src
 		senders[i] = func(msg tss.Message) {
-			msgBytes, _, err := msg.WireBytes()
+			msgBytes, routing, err := msg.WireBytes()
 			if err != nil {
 				log.Printf("Party %s failed to get wire bytes: %v", src.PartyID.Id, err)
 				return
 			}
-			round, isBroadcast, err := ClassifyMsg(msgBytes)
+			class, err := tss.ClassifyMessage(msgBytes)
 			if err != nil {
 				log.Printf("Party %s failed to classify message: %v", src.PartyID.Id, err)
 				return
 			}
-			log.Printf("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, round, isBroadcast)
-			if isBroadcast {
+			log.Printf("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, class.Round, class.IsBroadcastWith(routing))
+			if class.IsBroadcastWith(routing) {
 				for _, dst := range parties {
 					if dst.PartyID.Id != src.PartyID.Id {
-						dst.OnMsg(msg)
+						relay.Deliver(func() { dst.OnMsg(msg) })
 					}
 				}
 			} else {
@@ -257,7 +284,7 @@ func senders(parties []*EDDSAParty) []implement.Sender {
 				for _, recipient := range to {
 					for _, dst := range parties {
 						if recipient.Id == dst.PartyID.Id {
-							dst.OnMsg(msg)
+							relay.Deliver(func() { dst.OnMsg(msg) })
 							break
 						}
 					}
@@ -278,13 +305,13 @@ func senderForReshare(parties []*EDDSAParty) []implement.Sender {
 				log.Printf("Party %s failed to get wire bytes: %v", src.PartyID.Id, err)
 				return
 			}
-			round, isBroadcast, err := ClassifyMsg(msgBytes)
+			class, err := tss.ClassifyMessage(msgBytes)
 			if err != nil {
 				log.Printf("Party %s failed to classify message: %v", src.PartyID.Id, err)
 				return
 			}
-			if round != 7 {
-				log.Printf("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, round, isBroadcast)
+			if class.Protocol != tss.ProtocolResharing || class.Round != 4 {
+				log.Printf("Party %s received message, round: %d, isBroadcast: %t", src.PartyID.Id, class.Round, class.IsBroadcast())
 			}
 			to := msg.GetTo()
 			if to == nil {
@@ -294,7 +321,7 @@ func senderForReshare(parties []*EDDSAParty) []implement.Sender {
 			for _, recipient := range to {
 				for _, dst := range parties {
 					if recipient.Id == dst.PartyID.Id {
-						dst.OnMsg(msg)
+						relay.Deliver(func() { dst.OnMsg(msg) })
 						break
 					}
 				}
@@ -324,6 +351,181 @@ func encodeS256PubKey(pubKey *ecdsa.PublicKey) ([]byte, error) {
 	return publicKeyBytes, nil
 }
 
+func TestEDDSAPartyRunReturnsResults(t *testing.T) {
+	cfg := defaultTestConfig()
+	parties := setupTestParties(t, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// signing without a key share fails instead of panicking
+	_, err := parties[0].RunSign(ctx, cfg.messageToSign)
+	require.Error(t, err)
+
+	shares := make([]*keygen.LocalPartySaveData, len(parties))
+	errs := make([]error, len(parties))
+	var wg sync.WaitGroup
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *EDDSAParty) {
+			defer wg.Done()
+			shares[i], errs[i] = p.RunKeygen(ctx)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+	for i := range parties {
+		require.NoError(t, errs[i])
+		require.True(t, shares[0].EDDSAPub.Equals(shares[i].EDDSAPub))
+		bz, err := json.Marshal(shares[i])
+		require.NoError(t, err)
+		// the share of another party, or a share that does not match its key, is refused
+		require.Error(t, parties[(i+1)%len(parties)].SetShareData(bz))
+		broken := *shares[i]
+		broken.Xi = new(big.Int).Add(broken.Xi, big.NewInt(1))
+		brokenBz, err := json.Marshal(broken)
+		require.NoError(t, err)
+		require.Error(t, parties[i].SetShareData(brokenBz))
+		require.NoError(t, parties[i].SetShareData(bz))
+	}
+
+	sigs := make([]*common.SignatureData, len(parties))
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *EDDSAParty) {
+			defer wg.Done()
+			sigs[i], errs[i] = p.RunSign(ctx, cfg.messageToSign)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+	for i := range parties {
+		require.NoError(t, errs[i])
+		require.Equal(t, sigs[0].Signature, sigs[i].Signature)
+	}
+
+	// a party whose peers never answer returns the abort error
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = parties[0].RunSign(ctx, cfg.messageToSign)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClassifyMessage(t *testing.T) {
+	typeURL := func(msg proto.Message) string {
+		return "type.googleapis.com/" + string(proto.MessageName(msg))
+	}
+	cases := []struct {
+		msg       proto.Message
+		protocol  tss.Protocol
+		round     int
+		routing   tss.Routing
+		committee tss.Committee
+	}{
+		{&keygen.KGRound1Message{}, tss.ProtocolKeygen, 1, tss.Routing_BROADCAST, tss.Committee_ALL_PARTIES},
+		{&keygen.KGRound2Message1{}, tss.ProtocolKeygen, 2, tss.Routing_P2P, tss.Committee_ALL_PARTIES},
+		{&keygen.KGRound2Message2{}, tss.ProtocolKeygen, 2, tss.Routing_BROADCAST, tss.Committee_ALL_PARTIES},
+		{&signing.SignRound3Message{}, tss.ProtocolSigning, 3, tss.Routing_BROADCAST, tss.Committee_ALL_PARTIES},
+		{&resharing.DGRound2Message{}, tss.ProtocolResharing, 2, tss.Routing_BROADCAST, tss.Committee_OLD_COMMITTEE},
+		{&resharing.DGRound3Message1{}, tss.ProtocolResharing, 3, tss.Routing_P2P, tss.Committee_NEW_COMMITTEE},
+		{&resharing.DGRound4Message{}, tss.ProtocolResharing, 4, tss.Routing_BROADCAST, tss.Committee_OLD_AND_NEW_COMMITTEES},
+	}
+	for _, c := range cases {
+		class, err := tss.ClassifyTypeURL(typeURL(c.msg))
+		require.NoError(t, err)
+		require.Equal(t, tss.FamilyEdDSA, class.Family, class.String())
+		require.Equal(t, c.protocol, class.Protocol, class.String())
+		require.Equal(t, c.round, class.Round, class.String())
+		require.Equal(t, c.routing, class.Routing, class.String())
+		require.Equal(t, c.committee, class.Committee, class.String())
+	}
+
+	// the wire bytes of a message are classified without parsing its content
+	msg := keygen.NewKGRound1Message(tss.NewPartyID("party1", "party1", big.NewInt(1)), big.NewInt(42))
+	wireBytes, _, err := msg.WireBytes()
+	require.NoError(t, err)
+	class, err := tss.ClassifyMessage(wireBytes)
+	require.NoError(t, err)
+	require.Equal(t, msg.IsBroadcast(), class.IsBroadcast())
+	require.Equal(t, 1, class.Round)
+	round, isBroadcast, err := ClassifyMsg(wireBytes)
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), round)
+	require.True(t, isBroadcast)
+
+	class, err = tss.ClassifyTypeURL(typeURL(&tss.VersionHello{}))
+	require.NoError(t, err)
+	require.Empty(t, class.Protocol)
+	require.Equal(t, 0, class.Round)
+	_, err = tss.ClassifyTypeURL(typeURL(&tss.MessageWrapper{}))
+	require.ErrorIs(t, err, tss.ErrUnknownMessage)
+}
+
+func TestClassifyMsg(t *testing.T) {
+	// the deprecated ClassifyMsg numbers the rounds as it always has
+	cases := []struct {
+		msg         proto.Message
+		round       uint8
+		isBroadcast bool
+	}{
+		{&keygen.KGRound1Message{}, 1, true},
+		{&keygen.KGRound2Message1{}, 2, false},
+		{&keygen.KGRound2Message2{}, 3, true},
+		{&signing.SignRound1Message{}, 4, true},
+		{&signing.SignRound2Message{}, 1, true},
+		{&signing.SignRound3Message{}, 2, true},
+		{&resharing.DGRound1Message{}, 3, true},
+		{&resharing.DGRound3Message2{}, 6, false},
+		{&resharing.DGRound4Message{}, 7, true},
+		{&tss.VersionHello{}, 0, false},
+	}
+	for _, c := range cases {
+		wireBytes, err := proto.Marshal(&anypb.Any{TypeUrl: "type.googleapis.com/" + string(proto.MessageName(c.msg))})
+		require.NoError(t, err)
+		round, isBroadcast, err := ClassifyMsg(wireBytes)
+		require.NoError(t, err)
+		require.Equal(t, c.round, round, proto.MessageName(c.msg))
+		require.Equal(t, c.isBroadcast, isBroadcast, proto.MessageName(c.msg))
+	}
+	_, _, err := ClassifyMsg([]byte{0xff})
+	require.Error(t, err)
+}
+
+func TestPartyKeys(t *testing.T) {
+	ec := tss.Edwards()
+	long := strings.Repeat("a very long party id ", 10)
+
+	// the hashed keys are valid VSS indexes whatever the length of the ids
+	partyIDs, err := implement.CreateSortedPartyIDsWithKeys(ec, []string{"party1", long, long + "x"}, implement.PartyKey)
+	require.NoError(t, err)
+	for _, pID := range partyIDs {
+		require.True(t, pID.KeyInt().Sign() > 0)
+		require.True(t, pID.KeyInt().Cmp(ec.Params().N) < 0)
+	}
+
+	// ids whose legacy keys are equal modulo N are refused before keygen, unless the keys are hashed
+	other := string(new(big.Int).Add(new(big.Int).SetBytes([]byte("party1")), ec.Params().N).Bytes())
+	_, err = implement.CreateSortedPartyIDs(ec, []string{"party1", other})
+	require.Error(t, err)
+	partyIDs, err = implement.CreateSortedPartyIDsWithKeys(ec, []string{"party1", other}, implement.PartyKey)
+	require.NoError(t, err)
+	for _, pID := range partyIDs {
+		require.True(t, pID.KeyInt().Cmp(ec.Params().N) < 0)
+	}
+
+	_, err = implement.CreateSortedPartyIDs(ec, []string{"party1", "party1"})
+	require.Error(t, err)
+
+	// the legacy keys are kept by default, so that the existing key shares can still be used
+	party := NewEDDSAParty("party1")
+	require.Equal(t, new(big.Int).SetBytes([]byte("party1")), party.PartyID.KeyInt())
+	require.Error(t, party.Init([]string{"party1", other}, 1, nil))
+	party.SetPartyKeyFunc(implement.PartyKey)
+	require.Equal(t, implement.PartyKey(ec, "party1"), party.PartyID.KeyInt())
+
+	// a party without a curve cannot be initialized
+	require.Error(t, implement.NewBaseParty("party1").Init([]string{"party1", "party2"}, 1, nil))
+}
+
 func TestEDDSA_Generate10IndependentKeys(t *testing.T) {
 	cfg := testConfig{
 		threshold:     1,
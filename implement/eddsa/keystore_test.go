@@ -0,0 +1,67 @@
+package eddsa
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/implement"
+)
+
+func TestEDDSAPartyKeyStore(t *testing.T) {
+	cfg := defaultTestConfig()
+	parties := setupTestParties(t, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	stores := make([]*implement.FileKeyStore, len(parties))
+	for i, party := range parties {
+		ks, err := implement.NewFileKeyStore(t.TempDir(), []byte("passphrase"))
+		require.NoError(t, err)
+		stores[i] = ks
+		party.SetKeyStore(ks)
+	}
+	shares := make([]*keygen.LocalPartySaveData, len(parties))
+	errs := make([]error, len(parties))
+	var wg sync.WaitGroup
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *EDDSAParty) {
+			defer wg.Done()
+			shares[i], errs[i] = p.RunKeygen(ctx)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+
+	// every party finds its share by the fingerprint of the public key, and signs with it
+	fingerprint := implement.KeyFingerprint(shares[0].EDDSAPub)
+	for i, party := range parties {
+		require.NoError(t, errs[i])
+		fingerprints, err := stores[i].List()
+		require.NoError(t, err)
+		require.Equal(t, []string{fingerprint}, fingerprints)
+		require.NoError(t, party.LoadShareData(fingerprint))
+	}
+	sigs := make([]*common.SignatureData, len(parties))
+	for i, party := range parties {
+		wg.Add(1)
+		go func(i int, p *EDDSAParty) {
+			defer wg.Done()
+			sigs[i], errs[i] = p.RunSign(ctx, cfg.messageToSign)
+		}(i, party)
+	}
+	wg.Wait()
+	endSession(parties)
+	for i := range parties {
+		require.NoError(t, errs[i])
+		require.Equal(t, sigs[0].Signature, sigs[i].Signature)
+	}
+
+	require.ErrorIs(t, parties[0].LoadShareData("00112233445566778899aabbccddeeff"), implement.ErrKeyNotFound)
+}
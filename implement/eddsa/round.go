@@ -1,8 +1,8 @@
 package eddsa
 
 import (
-	"github.com/golang/protobuf/ptypes/any"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 var (
@@ -42,8 +42,14 @@ var (
 	}
 )
 
+// ClassifyMsg returns the round of the message in `msgBytes` and whether it is always broadcast. The rounds are numbered
+// as this function always has, which is not as tss.ClassifyMessage does, and a message of a type it does not know is
+// round 0 and not broadcast.
+//
+// Deprecated: use tss.ClassifyMessage, which returns the protocol, round, routing and committee of every message of the
+// library.
 func ClassifyMsg(msgBytes []byte) (uint8, bool, error) {
-	msg := &any.Any{}
+	msg := new(anypb.Any)
 	if err := proto.Unmarshal(msgBytes, msg); err != nil {
 		return 0, false, err
 	}
@@ -0,0 +1,155 @@
+package eddsa
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/implement"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// routeAll delivers the messages of every node to the session managers of their recipients
+func routeAll(t *testing.T, partyIDs tss.SortedPartyIDs, out chan tss.Message, managers []*implement.SessionManager) {
+	for msg := range out {
+		bz, routing, err := msg.WireBytes()
+		require.NoError(t, err)
+		to := msg.GetTo()
+		if to == nil {
+			to = partyIDs
+		}
+		for _, Pj := range to {
+			if Pj.Index != msg.GetFrom().Index {
+				require.NoError(t, managers[Pj.Index].Route(bz, routing))
+			}
+		}
+	}
+}
+
+func TestSessionManagerRunsConcurrentKeygens(t *testing.T) {
+	partyIDs, err := implement.CreateSortedPartyIDs(tss.Edwards(), []string{"party1", "party2", "party3"})
+	require.NoError(t, err)
+	n := len(partyIDs)
+	managers := make([]*implement.SessionManager, n)
+	for i := range managers {
+		managers[i] = implement.NewSessionManager(time.Minute)
+		defer managers[i].Close()
+	}
+	out := make(chan tss.Message, 1000)
+	defer close(out)
+	go routeAll(t, partyIDs, out, managers)
+
+	wallets := map[string][]byte{"wallet-a": []byte("session-a"), "wallet-b": []byte("session-b")}
+	ends := make(map[string]chan *keygen.LocalPartySaveData, len(wallets))
+	sessions := make([]*implement.Session, 0, n*len(wallets))
+	// the first node starts last, so that the messages of the others wait for its sessions
+	for i := n - 1; 0 <= i; i-- {
+		for keyID, sessionID := range wallets {
+			if ends[keyID] == nil {
+				ends[keyID] = make(chan *keygen.LocalPartySaveData, n)
+			}
+			params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(partyIDs), partyIDs[i], n, 1)
+			params.SetSessionID(sessionID)
+			party := keygen.NewLocalParty(params, out, ends[keyID])
+			s, err := managers[i].Start(context.Background(), keyID, sessionID, party)
+			require.NoError(t, err)
+			sessions = append(sessions, s)
+		}
+		if i == n-1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	_, err = managers[0].Start(context.Background(), "wallet-a", wallets["wallet-a"], nil)
+	require.Error(t, err, "a session id can only be used once")
+
+	for _, s := range sessions {
+		select {
+		case <-s.Done():
+			require.Nil(t, s.Err())
+		case <-time.After(time.Minute):
+			t.Fatal("a session did not finish")
+		}
+	}
+	for keyID, end := range ends {
+		var pub *keygen.LocalPartySaveData
+		for i := 0; i < n; i++ {
+			save := <-end
+			if pub == nil {
+				pub = save
+			}
+			require.True(t, pub.EDDSAPub.Equals(save.EDDSAPub), "the parties of %s agree on the key", keyID)
+		}
+	}
+	for _, m := range managers {
+		require.Len(t, m.Sessions("wallet-a"), 1)
+		require.Equal(t, len(wallets), m.Collect())
+		require.Empty(t, m.Sessions("wallet-a"))
+	}
+}
+
+func TestSessionManagerTimesOutSessions(t *testing.T) {
+	partyIDs, err := implement.CreateSortedPartyIDs(tss.Edwards(), []string{"party1", "party2", "party3"})
+	require.NoError(t, err)
+	n := len(partyIDs)
+	m := implement.NewSessionManager(500 * time.Millisecond)
+	defer m.Close()
+
+	// the other parties never answer
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(partyIDs), partyIDs[0], n, 1)
+	params.SetSessionID([]byte("session"))
+	out := make(chan tss.Message, 100)
+	party := keygen.NewLocalParty(params, out, make(chan *keygen.LocalPartySaveData, 1))
+	s, err := m.Start(context.Background(), "wallet", []byte("session"), party)
+	require.NoError(t, err)
+	select {
+	case <-s.Done():
+		require.NotNil(t, s.Err())
+		require.True(t, errors.Is(s.Err(), context.DeadlineExceeded))
+		require.NotEmpty(t, s.Err().Culprits())
+	case <-time.After(time.Minute):
+		t.Fatal("the session did not time out")
+	}
+	require.Equal(t, 1, m.Collect())
+	_, ok := m.Session([]byte("session"))
+	require.False(t, ok)
+
+	// a message for the collected session is kept as if it was for a session still to come
+	msg := <-out
+	bz, routing, err := msg.WireBytes()
+	require.NoError(t, err)
+	require.NoError(t, m.Route(bz, routing))
+}
+
+func TestSessionManagerBoundsPendingMessages(t *testing.T) {
+	partyIDs, err := implement.CreateSortedPartyIDs(tss.Edwards(), []string{"party1", "party2"})
+	require.NoError(t, err)
+	m := implement.NewSessionManager(0)
+	defer m.Close()
+	m.SetPendingLimits(2, 3, 200*time.Millisecond)
+
+	route := func(sessionID string) error {
+		params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(partyIDs), partyIDs[1], len(partyIDs), 1)
+		params.SetSessionID([]byte(sessionID))
+		msg := keygen.NewKGRound1Message(partyIDs[1], big.NewInt(1))
+		require.NoError(t, params.StampMessage(msg))
+		return m.RouteMessage(msg)
+	}
+	require.NoError(t, route("a"))
+	require.NoError(t, route("a"))
+	require.NoError(t, route("b"))
+	// no more session ids are kept, nor more messages in all
+	require.ErrorIs(t, route("c"), implement.ErrUnknownSession)
+	require.ErrorIs(t, route("b"), implement.ErrUnknownSession)
+
+	// the kept messages expire even though the sessions have no timeout
+	time.Sleep(300 * time.Millisecond)
+	m.Collect()
+	require.NoError(t, route("c"))
+	require.NoError(t, route("c"))
+	require.NoError(t, route("d"))
+}
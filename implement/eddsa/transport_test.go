@@ -0,0 +1,203 @@
+package eddsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/implement"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestEDDSAPartyOverMemoryHub(t *testing.T) {
+	cfg := defaultTestConfig()
+	hub := implement.NewMemoryHub()
+
+	parties := make([]*EDDSAParty, len(cfg.participants))
+	for i, id := range cfg.participants {
+		parties[i] = NewEDDSAParty(id)
+		parties[i].UseTransport(hub.Join(id))
+		require.NoError(t, parties[i].Init(cfg.participants, cfg.threshold, nil))
+		go parties[i].NotifyError()
+	}
+	defer cleanupTestParties(parties)
+
+	shares := keygenAll(parties)
+	require.Equal(t, len(cfg.participants), len(shares))
+	for _, party := range parties {
+		require.NoError(t, party.SetShareData(shares[party.PartyID.Id]))
+	}
+	sigs := signAll(parties, cfg.messageToSign)
+	require.Equal(t, len(cfg.participants), len(sigs))
+}
+
+func TestMemoryHubFullInbox(t *testing.T) {
+	hub := implement.NewMemoryHub()
+	a, b := hub.Join("a"), hub.Join("b")
+	from := tss.NewPartyID("a", "a", big.NewInt(1))
+	to := []*tss.PartyID{tss.NewPartyID("b", "b", big.NewInt(2))}
+	msg := keygen.NewKGRound1Message(from, big.NewInt(1))
+
+	// nobody reads the inbox of b, so the sends block once it is full
+	sent := make(chan error, 1)
+	go func() {
+		for {
+			if err := a.Send(to, msg); err != nil {
+				sent <- err
+				return
+			}
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// which neither blocks the parties that join nor those that leave
+	joined := make(chan struct{})
+	go func() {
+		hub.Join("c")
+		require.NoError(t, b.Close())
+		close(joined)
+	}()
+	select {
+	case <-joined:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a send to a full inbox blocked the hub")
+	}
+	select {
+	case err := <-sent:
+		require.Error(t, err, "the party left the hub")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the send to a party that left the hub did not return")
+	}
+	for range b.Receive() {
+	}
+	require.Error(t, a.Send(to, msg))
+	require.NoError(t, a.Close())
+}
+
+func TestEDDSAPartyOverTLS(t *testing.T) {
+	cfg := defaultTestConfig()
+	tlsConfigs := localhostTLSConfigs(t, cfg.participants)
+
+	transports := make([]*implement.TCPTransport, len(cfg.participants))
+	for i := range cfg.participants {
+		transport, err := implement.NewTCPTransport("127.0.0.1:0", tlsConfigs[i])
+		require.NoError(t, err)
+		transports[i] = transport
+	}
+	parties := make([]*EDDSAParty, len(cfg.participants))
+	for i, id := range cfg.participants {
+		for j, peer := range cfg.participants {
+			transports[i].AddPeer(peer, transports[j].Addr().String())
+		}
+		parties[i] = NewEDDSAParty(id)
+		parties[i].UseTransport(transports[i])
+		require.NoError(t, parties[i].Init(cfg.participants, cfg.threshold, nil))
+		go parties[i].NotifyError()
+	}
+	defer cleanupTestParties(parties)
+
+	shares := keygenAll(parties)
+	require.Equal(t, len(cfg.participants), len(shares))
+}
+
+func TestTCPTransportRejectsImpostors(t *testing.T) {
+	ids := []string{"a", "b", "c"}
+	tlsConfigs := localhostTLSConfigs(t, ids)
+	pIDs, err := implement.CreateSortedPartyIDs(tss.Edwards(), ids)
+	require.NoError(t, err)
+	byID := make(map[string]*tss.PartyID)
+	for _, Pj := range pIDs {
+		byID[Pj.Id] = Pj
+	}
+
+	a, err := implement.NewTCPTransport("127.0.0.1:0", tlsConfigs[0])
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := implement.NewTCPTransport("127.0.0.1:0", tlsConfigs[1])
+	require.NoError(t, err)
+	defer b.Close()
+	a.AddPeer("b", b.Addr().String())
+	b.SetParties(pIDs)
+
+	// a poses as c, then as a with the index of c, and only its own message gets through
+	asC := byID["c"]
+	withIndexOfC := tss.NewPartyID("a", "a", byID["a"].KeyInt())
+	withIndexOfC.Index = asC.Index
+	to := []*tss.PartyID{byID["b"]}
+	require.NoError(t, a.Send(to, keygen.NewKGRound1Message(asC, big.NewInt(1))))
+	require.NoError(t, a.Send(to, keygen.NewKGRound1Message(withIndexOfC, big.NewInt(2))))
+	require.NoError(t, a.Send(to, keygen.NewKGRound1Message(byID["a"], big.NewInt(3))))
+	select {
+	case msg := <-b.Receive():
+		require.Equal(t, "a", msg.GetFrom().Id)
+		require.Equal(t, byID["a"].Index, msg.GetFrom().Index)
+		commitment := msg.(tss.ParsedMessage).Content().(*keygen.KGRound1Message).UnmarshalCommitment()
+		require.Equal(t, big.NewInt(3), commitment)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the message of a was not received")
+	}
+	select {
+	case msg := <-b.Receive():
+		t.Fatalf("received a second message from %s", msg.GetFrom())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// localhostTLSConfigs returns the configs of parties with the given ids, each with a certificate for its id signed by
+// a common CA, which they use as both server and client
+func localhostTLSConfigs(t *testing.T, ids []string) []*tls.Config {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	configs := make([]*tls.Config, len(ids))
+	for i, id := range ids {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: id},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		configs[i] = &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}},
+			RootCAs:      pool,
+			ClientCAs:    pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			MinVersion:   tls.VersionTLS13,
+		}
+	}
+	return configs
+}
@@ -0,0 +1,224 @@
+package implement
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+const (
+	keyFileExt     = ".json"
+	keyFileVersion = 1
+
+	// scrypt parameters of new key files, as recommended for interactive use in 2017
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptMaxN   = 1 << 20
+	scryptKeyLen = 32
+	saltLen      = 32
+)
+
+// ErrKeyNotFound is returned by a KeyStore for a fingerprint that it has no key for
+var ErrKeyNotFound = errors.New("key not found")
+
+type (
+	// KeyStore keeps the save data of the keys of a party, which hold its secret shares, by the fingerprint of their
+	// public key. See ECDSAParty.SetKeyStore and EDDSAParty.SetKeyStore.
+	KeyStore interface {
+		// Put stores the JSON save data of the key `pub`, replacing any that it had, and returns its fingerprint
+		Put(pub *crypto.ECPoint, saveData []byte) (string, error)
+		// Get returns the JSON save data stored under `fingerprint`
+		Get(fingerprint string) ([]byte, error)
+		// List returns the fingerprints of the stored keys in ascending order
+		List() ([]string, error)
+		Delete(fingerprint string) error
+	}
+
+	// FileKeyStore is a KeyStore that writes each key to a file of its own in a directory, encrypted with AES-GCM under
+	// a key derived from a passphrase with scrypt
+	FileKeyStore struct {
+		dir        string
+		passphrase []byte
+	}
+
+	// keyFile is the JSON form of a file of a FileKeyStore
+	keyFile struct {
+		Version     int    `json:"version"`
+		Fingerprint string `json:"fingerprint"`
+		KDF         string `json:"kdf"`
+		N           int    `json:"n"`
+		R           int    `json:"r"`
+		P           int    `json:"p"`
+		Salt        []byte `json:"salt"`
+		Nonce       []byte `json:"nonce"`
+		Ciphertext  []byte `json:"ciphertext"`
+	}
+)
+
+var _ KeyStore = (*FileKeyStore)(nil)
+
+// KeyFingerprint identifies a public key by the hex of the first 16 bytes of the SHA-512/256 of its coordinates
+func KeyFingerprint(pub *crypto.ECPoint) string {
+	return hex.EncodeToString(common.SHA512_256(pub.X().Bytes(), pub.Y().Bytes())[:16])
+}
+
+// NewFileKeyStore opens the key store in `dir`, creating the directory if needed. Every key is encrypted under `passphrase`.
+func NewFileKeyStore(dir string, passphrase []byte) (*FileKeyStore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("the key store needs a passphrase")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileKeyStore{dir: dir, passphrase: append([]byte{}, passphrase...)}, nil
+}
+
+func (ks *FileKeyStore) Put(pub *crypto.ECPoint, saveData []byte) (string, error) {
+	if pub == nil {
+		return "", errors.New("cannot store a key without its public key")
+	}
+	fingerprint := KeyFingerprint(pub)
+	kf := &keyFile{
+		Version:     keyFileVersion,
+		Fingerprint: fingerprint,
+		KDF:         "scrypt",
+		N:           scryptN,
+		R:           scryptR,
+		P:           scryptP,
+		Salt:        make([]byte, saltLen),
+	}
+	if _, err := rand.Read(kf.Salt); err != nil {
+		return "", err
+	}
+	aead, err := ks.aead(kf)
+	if err != nil {
+		return "", err
+	}
+	kf.Nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(kf.Nonce); err != nil {
+		return "", err
+	}
+	kf.Ciphertext = aead.Seal(nil, kf.Nonce, saveData, []byte(fingerprint))
+	bz, err := json.Marshal(kf)
+	if err != nil {
+		return "", err
+	}
+	// write then rename, so that a crash never leaves a key half written
+	tmp, err := os.CreateTemp(ks.dir, fingerprint+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(bz); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp.Name(), ks.path(fingerprint)); err != nil {
+		return "", err
+	}
+	return fingerprint, nil
+}
+
+func (ks *FileKeyStore) Get(fingerprint string) ([]byte, error) {
+	if !validFingerprint(fingerprint) {
+		return nil, fmt.Errorf("%w: %q is not a fingerprint", ErrKeyNotFound, fingerprint)
+	}
+	bz, err := os.ReadFile(ks.path(fingerprint))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, fingerprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	kf := new(keyFile)
+	if err = json.Unmarshal(bz, kf); err != nil {
+		return nil, fmt.Errorf("key %s: %w", fingerprint, err)
+	}
+	if kf.Version != keyFileVersion || kf.KDF != "scrypt" || kf.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("key %s: unsupported or mismatched key file", fingerprint)
+	}
+	aead, err := ks.aead(kf)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", fingerprint, err)
+	}
+	if len(kf.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("key %s: invalid nonce", fingerprint)
+	}
+	saveData, err := aead.Open(nil, kf.Nonce, kf.Ciphertext, []byte(fingerprint))
+	if err != nil {
+		return nil, fmt.Errorf("key %s: wrong passphrase or corrupted key file", fingerprint)
+	}
+	return saveData, nil
+}
+
+func (ks *FileKeyStore) List() ([]string, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+	fingerprints := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		fingerprint := strings.TrimSuffix(entry.Name(), keyFileExt)
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), keyFileExt) && validFingerprint(fingerprint) {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	sort.Strings(fingerprints)
+	return fingerprints, nil
+}
+
+func (ks *FileKeyStore) Delete(fingerprint string) error {
+	if !validFingerprint(fingerprint) {
+		return fmt.Errorf("%w: %q is not a fingerprint", ErrKeyNotFound, fingerprint)
+	}
+	err := os.Remove(ks.path(fingerprint))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrKeyNotFound, fingerprint)
+	}
+	return err
+}
+
+func (ks *FileKeyStore) path(fingerprint string) string {
+	return filepath.Join(ks.dir, fingerprint+keyFileExt)
+}
+
+// aead derives the key of a key file from the passphrase with the scrypt parameters of the file
+func (ks *FileKeyStore) aead(kf *keyFile) (cipher.AEAD, error) {
+	if kf.N < 2 || scryptMaxN < kf.N || kf.R < 1 || kf.P < 1 || len(kf.Salt) != saltLen {
+		return nil, errors.New("invalid scrypt parameters")
+	}
+	key, err := scrypt.Key(ks.passphrase, kf.Salt, kf.N, kf.R, kf.P, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// a fingerprint is lowercase hex, which also keeps it from naming a file outside of the directory
+func validFingerprint(fingerprint string) bool {
+	bz, err := hex.DecodeString(fingerprint)
+	return err == nil && len(bz) == 16 && hex.EncodeToString(bz) == fingerprint
+}
@@ -0,0 +1,57 @@
+package implement
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestFileKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := NewFileKeyStore(dir, []byte("passphrase"))
+	require.NoError(t, err)
+
+	pub := crypto.ScalarBaseMult(tss.Edwards(), common.GetRandomPositiveInt(rand.Reader, tss.Edwards().Params().N))
+	saveData := []byte(`{"Xi":"secret share"}`)
+	fingerprint, err := ks.Put(pub, saveData)
+	require.NoError(t, err)
+	require.Equal(t, KeyFingerprint(pub), fingerprint)
+
+	fingerprints, err := ks.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{fingerprint}, fingerprints)
+	got, err := ks.Get(fingerprint)
+	require.NoError(t, err)
+	require.Equal(t, saveData, got)
+
+	// the file does not hold the save data in the clear
+	path := filepath.Join(dir, fingerprint+".json")
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(bz, []byte("secret share")))
+
+	other, err := NewFileKeyStore(dir, []byte("another passphrase"))
+	require.NoError(t, err)
+	_, err = other.Get(fingerprint)
+	require.Error(t, err, "a wrong passphrase does not decrypt the key")
+
+	// a key file cannot be passed off as the key of another fingerprint
+	otherPub := crypto.ScalarBaseMult(tss.Edwards(), common.GetRandomPositiveInt(rand.Reader, tss.Edwards().Params().N))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, KeyFingerprint(otherPub)+".json"), bz, 0600))
+	_, err = ks.Get(KeyFingerprint(otherPub))
+	require.Error(t, err)
+
+	_, err = ks.Get("../" + fingerprint)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.NoError(t, ks.Delete(fingerprint))
+	_, err = ks.Get(fingerprint)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
@@ -0,0 +1,106 @@
+package implement
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// MemoryHub connects parties that run in the same process, e.g. in tests
+type MemoryHub struct {
+	mtx     sync.RWMutex
+	members map[string]*memoryTransport
+}
+
+type memoryTransport struct {
+	hub  *MemoryHub
+	id   string
+	recv chan tss.Message
+	// done is closed when the transport leaves the hub; recv is closed once the sends in flight to it are over
+	done    chan struct{}
+	senders sync.WaitGroup
+	once    sync.Once
+}
+
+func NewMemoryHub() *MemoryHub {
+	return &MemoryHub{
+		members: make(map[string]*memoryTransport),
+	}
+}
+
+// Join returns the transport of the party with the given id, replacing any earlier one
+func (h *MemoryHub) Join(partyID string) Transport {
+	t := &memoryTransport{
+		hub:  h,
+		id:   partyID,
+		recv: make(chan tss.Message, defaultChanSize),
+		done: make(chan struct{}),
+	}
+	h.mtx.Lock()
+	prev := h.members[partyID]
+	h.members[partyID] = t
+	h.mtx.Unlock()
+	if prev != nil {
+		prev.shutdown()
+	}
+	return t
+}
+
+func (t *memoryTransport) Send(to []*tss.PartyID, msg tss.Message) error {
+	t.hub.mtx.RLock()
+	if t.hub.members[t.id] != t {
+		t.hub.mtx.RUnlock()
+		return errors.New("memory transport: send on a closed transport")
+	}
+	dsts := make([]*memoryTransport, 0, len(to))
+	for _, Pj := range to {
+		dst, ok := t.hub.members[Pj.Id]
+		if !ok {
+			t.hub.mtx.RUnlock()
+			for _, dst := range dsts {
+				dst.senders.Done()
+			}
+			return fmt.Errorf("memory transport: party %s has not joined the hub", Pj.Id)
+		}
+		dst.senders.Add(1)
+		dsts = append(dsts, dst)
+	}
+	t.hub.mtx.RUnlock()
+
+	// a full inbox must not hold the lock of the hub, which Join and Close take
+	var err error
+	for _, dst := range dsts {
+		select {
+		case dst.recv <- msg:
+		case <-dst.done:
+			err = fmt.Errorf("memory transport: party %s left the hub", dst.id)
+		}
+		dst.senders.Done()
+	}
+	return err
+}
+
+func (t *memoryTransport) Receive() <-chan tss.Message {
+	return t.recv
+}
+
+func (t *memoryTransport) Close() error {
+	t.hub.mtx.Lock()
+	if t.hub.members[t.id] == t {
+		delete(t.hub.members, t.id)
+	}
+	t.hub.mtx.Unlock()
+	t.shutdown()
+	return nil
+}
+
+// shutdown stops the sends to the transport, which has left the hub, and closes its inbox once they are over
+func (t *memoryTransport) shutdown() {
+	t.once.Do(func() {
+		close(t.done)
+		t.senders.Wait()
+		close(t.recv)
+	})
+}
@@ -0,0 +1,333 @@
+package implement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// maxPendingMessages is how many messages are kept for a session that was not started yet on this node
+	maxPendingMessages = 1000
+	// defaultPendingSessions and defaultPendingTotal bound the sessions not started yet that messages are kept for,
+	// and the messages kept for all of them
+	defaultPendingSessions = 100
+	defaultPendingTotal    = 10000
+	// defaultPendingTimeout is how long the messages of a session not started yet are kept when there is no session timeout
+	defaultPendingTimeout = time.Minute
+)
+
+// ErrUnknownSession is returned when a message cannot be routed because its session is over or was never started
+var ErrUnknownSession = errors.New("unknown session")
+
+type (
+	// SessionManager runs many keygen, signing and resharing parties of a node at once.
+	// Every party must be given its own session id with tss.Parameters.SetSessionID, and the manager routes each received
+	// message to the party of the session id it carries. Messages that arrive before their session was started are kept
+	// until it is, or until they expire, within the limits of SetPendingLimits. Completed, aborted and timed-out sessions
+	// and the expired messages are removed by Collect.
+	SessionManager struct {
+		// ErrChan receives the errors of messages that were rejected without ending their session
+		ErrChan chan error
+
+		mtx             sync.Mutex
+		sessions        map[string]*Session
+		pending         map[string]*pendingMessages
+		pendingCount    int
+		pendingSessions int
+		pendingTotal    int
+		pendingTimeout  time.Duration
+		timeout         time.Duration
+		closed          bool
+	}
+
+	// Session is a party run by a SessionManager
+	Session struct {
+		KeyID     string
+		SessionID []byte
+		Party     tss.Party
+		Started   time.Time
+
+		inbox  chan tss.ParsedMessage
+		cancel context.CancelFunc
+		done   chan struct{}
+		once   sync.Once
+		err    *tss.Error
+	}
+
+	pendingMessages struct {
+		msgs  []tss.ParsedMessage
+		since time.Time
+	}
+)
+
+// NewSessionManager creates a manager whose sessions are aborted with context.DeadlineExceeded when they run for longer
+// than `timeout`, if it is positive
+func NewSessionManager(timeout time.Duration) *SessionManager {
+	pendingTimeout := timeout
+	if pendingTimeout <= 0 {
+		pendingTimeout = defaultPendingTimeout
+	}
+	return &SessionManager{
+		ErrChan:         make(chan error, defaultChanSize),
+		sessions:        make(map[string]*Session),
+		pending:         make(map[string]*pendingMessages),
+		pendingSessions: defaultPendingSessions,
+		pendingTotal:    defaultPendingTotal,
+		pendingTimeout:  pendingTimeout,
+		timeout:         timeout,
+	}
+}
+
+// SetPendingLimits bounds the messages kept for sessions that were not started yet: those of at most `sessions` session
+// ids are kept, at most `total` in all, and each for at most `timeout`. By default 100 session ids and 10000 messages
+// are kept for the session timeout, or for a minute without one.
+func (m *SessionManager) SetPendingLimits(sessions, total int, timeout time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.pendingSessions, m.pendingTotal, m.pendingTimeout = sessions, total, timeout
+}
+
+// Start registers `party` under `sessionID` and starts it, then passes it the messages of its session.
+// `party` must not be started yet; `keyID` names the key that the session creates or uses, see Sessions.
+func (m *SessionManager) Start(ctx context.Context, keyID string, sessionID []byte, party tss.Party) (*Session, error) {
+	if len(sessionID) == 0 {
+		return nil, errors.New("a session needs a session id")
+	}
+	var cancel context.CancelFunc
+	if 0 < m.timeout {
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	s := &Session{
+		KeyID:     keyID,
+		SessionID: sessionID,
+		Party:     party,
+		Started:   time.Now(),
+		inbox:     make(chan tss.ParsedMessage, defaultChanSize),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+
+	m.mtx.Lock()
+	if m.closed {
+		m.mtx.Unlock()
+		cancel()
+		return nil, errors.New("the session manager is closed")
+	}
+	if _, ok := m.sessions[string(sessionID)]; ok {
+		m.mtx.Unlock()
+		cancel()
+		return nil, fmt.Errorf("session %x is already running", sessionID)
+	}
+	m.sessions[string(sessionID)] = s
+	var early []tss.ParsedMessage
+	if pending, ok := m.pending[string(sessionID)]; ok {
+		early = pending.msgs
+		m.dropPending(string(sessionID))
+	}
+	m.mtx.Unlock()
+
+	go s.run(ctx, m.ErrChan, early)
+	return s, nil
+}
+
+// Route parses a message received from the transport and passes it to the party of its session
+func (m *SessionManager) Route(wireBytes []byte, routing *tss.MessageRouting) error {
+	msg, err := tss.ParseWireMessageWithRouting(wireBytes, routing)
+	if err != nil {
+		return err
+	}
+	return m.RouteMessage(msg)
+}
+
+// RouteMessage passes a parsed message to the party of its session, or keeps it until that session is started
+func (m *SessionManager) RouteMessage(msg tss.ParsedMessage) error {
+	sessionID := msg.WireMsg().GetSessionId()
+	if len(sessionID) == 0 {
+		return fmt.Errorf("%w: the message from %s has no session id", ErrUnknownSession, msg.GetFrom())
+	}
+	m.mtx.Lock()
+	s, ok := m.sessions[string(sessionID)]
+	if !ok {
+		defer m.mtx.Unlock()
+		if m.closed {
+			return errors.New("the session manager is closed")
+		}
+		pending, ok := m.pending[string(sessionID)]
+		if !ok || m.pendingTotal <= m.pendingCount {
+			m.expirePending()
+		}
+		if !ok {
+			if m.pendingSessions <= len(m.pending) {
+				return fmt.Errorf("%w: too many sessions not started yet to keep the message for session %x", ErrUnknownSession, sessionID)
+			}
+			pending = &pendingMessages{since: time.Now()}
+			m.pending[string(sessionID)] = pending
+		}
+		if maxPendingMessages <= len(pending.msgs) || m.pendingTotal <= m.pendingCount {
+			return fmt.Errorf("%w: too many messages for session %x", ErrUnknownSession, sessionID)
+		}
+		pending.msgs = append(pending.msgs, msg)
+		m.pendingCount++
+		return nil
+	}
+	m.mtx.Unlock()
+	select {
+	case s.inbox <- msg:
+		return nil
+	case <-s.done:
+		return fmt.Errorf("%w: session %x is over", ErrUnknownSession, sessionID)
+	}
+}
+
+// Session returns the running or finished session of `sessionID`, until it is collected
+func (m *SessionManager) Session(sessionID []byte) (*Session, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	s, ok := m.sessions[string(sessionID)]
+	return s, ok
+}
+
+// Sessions returns the sessions of the key `keyID` that were not collected yet
+func (m *SessionManager) Sessions(keyID string) []*Session {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	sessions := make([]*Session, 0)
+	for _, s := range m.sessions {
+		if s.KeyID == keyID {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
+
+// Collect removes the sessions that are over, and the messages kept for sessions that were not started in time.
+// It returns the number of sessions removed. Call it periodically, e.g. with RunCollector.
+func (m *SessionManager) Collect() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	removed := 0
+	for id, s := range m.sessions {
+		select {
+		case <-s.done:
+			delete(m.sessions, id)
+			removed++
+		default:
+		}
+	}
+	m.expirePending()
+	return removed
+}
+
+// expirePending drops the messages kept for the sessions that were not started within the pending timeout
+func (m *SessionManager) expirePending() {
+	for id, pending := range m.pending {
+		if m.pendingTimeout < time.Since(pending.since) {
+			m.dropPending(id)
+		}
+	}
+}
+
+func (m *SessionManager) dropPending(id string) {
+	m.pendingCount -= len(m.pending[id].msgs)
+	delete(m.pending, id)
+}
+
+// RunCollector calls Collect every `interval` until ctx is done
+func (m *SessionManager) RunCollector(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Collect()
+		}
+	}
+}
+
+// Close aborts every running session and drops the kept messages
+func (m *SessionManager) Close() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.closed = true
+	for _, s := range m.sessions {
+		s.cancel()
+	}
+	m.pending = make(map[string]*pendingMessages)
+	m.pendingCount = 0
+}
+
+// ----- //
+
+// Done is closed when the party of the session finished, failed or was aborted
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that ended the session, or nil if it completed or is still running
+func (s *Session) Err() *tss.Error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Session) run(ctx context.Context, errCh chan<- error, early []tss.ParsedMessage) {
+	defer s.cancel()
+	if err := s.Party.StartWithContext(ctx); err != nil {
+		s.finish(err)
+		return
+	}
+	aborted := s.Party.Aborted()
+	update := func(msg tss.ParsedMessage) bool {
+		if _, err := s.Party.Update(msg); err != nil {
+			// a message that was rejected does not stop the round, but a failed round does
+			if isFatal(err) {
+				s.finish(err)
+				return false
+			}
+			select {
+			case errCh <- err:
+			default:
+			}
+		}
+		if !s.Party.Running() {
+			s.finish(nil)
+			return false
+		}
+		return true
+	}
+	for _, msg := range early {
+		if !update(msg) {
+			return
+		}
+	}
+	for {
+		select {
+		case err := <-aborted:
+			s.finish(err)
+			return
+		case msg := <-s.inbox:
+			if !update(msg) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Session) finish(err *tss.Error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
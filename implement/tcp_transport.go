@@ -0,0 +1,353 @@
+package implement
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// frames are a header of the length of the payload and the sender's party index, followed by the payload
+	frameHeaderSize = 8
+	maxFrameSize    = 16 << 20
+)
+
+type (
+	// TCPTransport connects a party to its peers over TCP, secured with TLS when a tls.Config is given.
+	// Every message is sent as its whole MessageWrapper, so the routing metadata and session id travel with it.
+	// The sender of a message is the party that authenticated the connection it came on, never the one named in it.
+	TCPTransport struct {
+		tlsConfig *tls.Config
+		listener  net.Listener
+		recv      chan tss.Message
+		done      chan struct{}
+
+		mtx      sync.Mutex
+		peers    map[string]string    // party id -> address
+		conns    map[string]*peerConn // outgoing connections by party id
+		inbound  map[net.Conn]struct{}
+		parties  []*tss.PartyID
+		identify PeerIdentity
+		closed   bool
+		wg       sync.WaitGroup
+	}
+
+	// PeerIdentity returns the id of the party that a peer authenticated as with its TLS certificate
+	PeerIdentity func(cert *x509.Certificate) (partyID string, err error)
+
+	// peerConn serialises the frames written to one outgoing connection
+	peerConn struct {
+		mtx  sync.Mutex
+		conn net.Conn
+	}
+)
+
+// NewTCPTransport listens on `listenAddr`; a nil `tlsConfig` sends messages in the clear and does not authenticate the
+// peers, which is only fit for testing. The tls.Config must require and verify client certificates: a connection
+// without one is refused, and the party of a connection is named by its certificate, see SetPeerIdentity.
+func NewTCPTransport(listenAddr string, tlsConfig *tls.Config) (*TCPTransport, error) {
+	var listener net.Listener
+	var err error
+	if tlsConfig != nil {
+		listener, err = tls.Listen("tcp", listenAddr, tlsConfig)
+	} else {
+		listener, err = net.Listen("tcp", listenAddr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &TCPTransport{
+		tlsConfig: tlsConfig,
+		listener:  listener,
+		recv:      make(chan tss.Message, defaultChanSize),
+		done:      make(chan struct{}),
+		peers:     make(map[string]string),
+		conns:     make(map[string]*peerConn),
+		inbound:   make(map[net.Conn]struct{}),
+		identify:  CommonNamePeerIdentity,
+	}
+	t.wg.Add(1)
+	go t.accept()
+	return t, nil
+}
+
+// Addr is the address that the transport listens on
+func (t *TCPTransport) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+// AddPeer sets the address that messages to the party with id `partyID` are sent to
+func (t *TCPTransport) AddPeer(partyID, addr string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.peers[partyID] = addr
+}
+
+// SetParties sets the parties of the session, which the sender of every message must be one of. Init and InitReshare
+// set them on the transport of a BaseParty, so a frame received before is dropped.
+func (t *TCPTransport) SetParties(parties []*tss.PartyID) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.parties = parties
+}
+
+// SetPeerIdentity changes how the party of a connection is named by its certificate, which is
+// CommonNamePeerIdentity by default
+func (t *TCPTransport) SetPeerIdentity(identify PeerIdentity) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.identify = identify
+}
+
+// CommonNamePeerIdentity names the party of a certificate by its subject common name
+func CommonNamePeerIdentity(cert *x509.Certificate) (string, error) {
+	if cert.Subject.CommonName == "" {
+		return "", errors.New("the certificate has no common name")
+	}
+	return cert.Subject.CommonName, nil
+}
+
+func (t *TCPTransport) Send(to []*tss.PartyID, msg tss.Message) error {
+	payload, err := proto.Marshal(msg.WireMsg())
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], uint32(int32(msg.GetFrom().Index)))
+	frame = append(frame, payload...)
+
+	for _, Pj := range to {
+		pc, err := t.conn(Pj.Id)
+		if err != nil {
+			return err
+		}
+		pc.mtx.Lock()
+		_, err = pc.conn.Write(frame)
+		pc.mtx.Unlock()
+		if err != nil {
+			t.mtx.Lock()
+			if t.conns[Pj.Id] == pc {
+				delete(t.conns, Pj.Id)
+			}
+			t.mtx.Unlock()
+			pc.conn.Close()
+			return fmt.Errorf("tcp transport: failed to send to %s: %w", Pj.Id, err)
+		}
+	}
+	return nil
+}
+
+func (t *TCPTransport) Receive() <-chan tss.Message {
+	return t.recv
+}
+
+func (t *TCPTransport) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	err := t.listener.Close()
+	for _, pc := range t.conns {
+		pc.conn.Close()
+	}
+	for conn := range t.inbound {
+		conn.Close()
+	}
+	t.mtx.Unlock()
+
+	t.wg.Wait()
+	close(t.recv)
+	return err
+}
+
+// conn returns the outgoing connection to a party, connecting to it first if needed
+func (t *TCPTransport) conn(partyID string) (*peerConn, error) {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil, errors.New("tcp transport: send on a closed transport")
+	}
+	if pc, ok := t.conns[partyID]; ok {
+		t.mtx.Unlock()
+		return pc, nil
+	}
+	addr, ok := t.peers[partyID]
+	t.mtx.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("tcp transport: no address for party %s", partyID)
+	}
+
+	// dial without holding the mutex; the TLS handshake needs the peer, which may be dialing us at the same time
+	var conn net.Conn
+	var err error
+	if t.tlsConfig != nil {
+		conn, err = tls.Dial("tcp", addr, t.tlsConfig)
+	} else {
+		conn, err = net.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tcp transport: failed to connect to %s: %w", partyID, err)
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.closed {
+		conn.Close()
+		return nil, errors.New("tcp transport: send on a closed transport")
+	}
+	if pc, ok := t.conns[partyID]; ok { // another sender connected first
+		conn.Close()
+		return pc, nil
+	}
+	pc := &peerConn{conn: conn}
+	t.conns[partyID] = pc
+	return pc, nil
+}
+
+func (t *TCPTransport) accept() {
+	defer t.wg.Done()
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+		t.mtx.Lock()
+		if t.closed {
+			t.mtx.Unlock()
+			conn.Close()
+			return
+		}
+		t.inbound[conn] = struct{}{}
+		t.wg.Add(1)
+		t.mtx.Unlock()
+		go t.read(conn)
+	}
+}
+
+func (t *TCPTransport) read(conn net.Conn) {
+	defer t.wg.Done()
+	defer func() {
+		t.mtx.Lock()
+		delete(t.inbound, conn)
+		t.mtx.Unlock()
+		conn.Close()
+	}()
+	peerID, err := t.authenticate(conn)
+	if err != nil {
+		log.Printf("tcp transport: dropping connection from %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+	r := bufio.NewReader(conn)
+	header := make([]byte, frameHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return
+		}
+		size := binary.BigEndian.Uint32(header[:4])
+		if maxFrameSize < size {
+			log.Printf("tcp transport: dropping connection from %s, frame of %d bytes is too large", conn.RemoteAddr(), size)
+			return
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+		wire := new(tss.MessageWrapper)
+		if err := proto.Unmarshal(payload, wire); err != nil {
+			log.Printf("tcp transport: dropping connection from %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+		from, err := t.sender(peerID, int(int32(binary.BigEndian.Uint32(header[4:]))), wire)
+		if err != nil {
+			log.Printf("tcp transport: dropping a frame from %s: %v", conn.RemoteAddr(), err)
+			continue
+		}
+		msg, err := decodeFrame(from, wire)
+		if err != nil {
+			log.Printf("tcp transport: dropping connection from %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+		select {
+		case t.recv <- msg:
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// authenticate returns the id of the party that authenticated `conn` with its certificate, or "" without TLS
+func (t *TCPTransport) authenticate(conn net.Conn) (string, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return "", nil
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return "", err
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", errors.New("the peer sent no certificate")
+	}
+	t.mtx.Lock()
+	identify := t.identify
+	t.mtx.Unlock()
+	return identify(certs[0])
+}
+
+// sender resolves the sender of a frame among the parties of the session. It must be the party `peerID` of the
+// connection, with the index in the header and the key in the message; without TLS, any party of the session.
+func (t *TCPTransport) sender(peerID string, fromIndex int, wire *tss.MessageWrapper) (*tss.PartyID, error) {
+	if wire.GetFrom() == nil {
+		return nil, errors.New("received a message without a sender")
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.parties == nil {
+		return nil, errors.New("the parties of the session are not set")
+	}
+	key := new(big.Int).SetBytes(wire.From.GetKey())
+	for _, Pj := range t.parties {
+		if (peerID == "" || Pj.Id == peerID) && Pj.Index == fromIndex && Pj.KeyInt().Cmp(key) == 0 {
+			from := tss.NewPartyID(Pj.Id, Pj.Moniker, Pj.KeyInt())
+			from.Index = Pj.Index
+			return from, nil
+		}
+	}
+	if peerID == "" {
+		return nil, fmt.Errorf("the sender %s with index %d is not a party of the session", wire.From.GetId(), fromIndex)
+	}
+	return nil, fmt.Errorf("party %s sent a message as %s with index %d", peerID, wire.From.GetId(), fromIndex)
+}
+
+func decodeFrame(from *tss.PartyID, wire *tss.MessageWrapper) (tss.ParsedMessage, error) {
+	if wire.GetMessage() == nil {
+		return nil, errors.New("received a message without content")
+	}
+	content, err := proto.Marshal(wire.GetMessage())
+	if err != nil {
+		return nil, err
+	}
+	return tss.ParseWireMessageWithRouting(content, &tss.MessageRouting{
+		From:        from,
+		IsBroadcast: wire.GetIsBroadcast(),
+		SessionID:   wire.GetSessionId(),
+		Version:     wire.GetVersion(),
+		Signature:   wire.GetSignature(),
+	})
+}
@@ -0,0 +1,63 @@
+package implement
+
+import (
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Transport moves the messages of one party to the other parties of a session
+type Transport interface {
+	// Send delivers msg to each of the parties in `to`
+	Send(to []*tss.PartyID, msg tss.Message) error
+	// Receive returns the messages that other parties sent to this party; it is closed by Close
+	Receive() <-chan tss.Message
+	Close() error
+}
+
+// partyChecker is a Transport that checks the senders of the messages it receives against the parties of the session
+type partyChecker interface {
+	SetParties(parties []*tss.PartyID)
+}
+
+// setTransportParties tells the transport of the party, if it checks the senders, the parties of the session
+func (p *BaseParty) setTransportParties(parties []*tss.PartyID) {
+	if checker, ok := p.transport.(partyChecker); ok {
+		checker.SetParties(parties)
+	}
+}
+
+// UseTransport routes the messages of this party through t instead of the Sender passed to Init or InitReshare,
+// which may then be nil. It must be called before Init or InitReshare.
+func (p *BaseParty) UseTransport(t Transport) {
+	p.transport = t
+	go func() {
+		for msg := range t.Receive() {
+			p.OnMsg(msg)
+		}
+	}()
+}
+
+// Recipients resolves the parties that msg must be delivered to: the parties in msg.GetTo(),
+// or every other party of the committees it is meant for when it is a broadcast
+func (p *BaseParty) Recipients(msg tss.Message) []*tss.PartyID {
+	if to := msg.GetTo(); to != nil {
+		return to
+	}
+	var all []*tss.PartyID
+	switch {
+	case p.ReshareParams != nil && msg.IsToOldAndNewCommittees():
+		all = p.ReshareParams.OldAndNewParties()
+	case p.ReshareParams != nil && msg.IsToOldCommittee():
+		all = p.ReshareParams.OldParties().IDs()
+	case p.Params != nil:
+		all = p.Params.Parties().IDs()
+	case p.ReshareParams != nil:
+		all = p.ReshareParams.NewParties().IDs()
+	}
+	recipients := make([]*tss.PartyID, 0, len(all))
+	for _, Pj := range all {
+		if Pj.Id != p.PartyID.Id {
+			recipients = append(recipients, Pj)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,66 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Session holds the parameters of the parties of a test session, by party index
+type Session struct {
+	PartyIDs tss.SortedPartyIDs
+	Params   []*tss.Parameters
+}
+
+// NewSession creates the parameters of the parties `pIDs` on `ec`, applying `configure` to those of every party
+// when it is not nil
+func NewSession(ec elliptic.Curve, pIDs tss.SortedPartyIDs, threshold int, configure func(i int, params *tss.Parameters)) *Session {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := make([]*tss.Parameters, len(pIDs))
+	for i, pID := range pIDs {
+		params[i] = tss.NewParameters(ec, p2pCtx, pID, len(pIDs), threshold)
+		if configure != nil {
+			configure(i, params[i])
+		}
+	}
+	return &Session{PartyIDs: pIDs, Params: params}
+}
+
+// SetIdentityKeys gives every party a new identity key and the public identity keys of all parties
+func (s *Session) SetIdentityKeys() error {
+	peerKeys := tss.NewPeerKeys()
+	for i, pID := range s.PartyIDs {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		if err := peerKeys.Add(pID, pub); err != nil {
+			return err
+		}
+		s.Params[i].SetSigner(priv)
+		s.Params[i].SetPeerKeys(peerKeys)
+	}
+	return nil
+}
+
+// RouteMessage passes `msg` with SharedPartyUpdater to the parties that it is addressed to, which are found by their
+// index in `parties`. A broadcast is passed to every party but its sender.
+func RouteMessage(parties []tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	if dest := msg.GetTo(); dest != nil {
+		for _, Pj := range dest {
+			SharedPartyUpdater(parties[Pj.Index], msg, errCh)
+		}
+		return
+	}
+	for _, P := range parties {
+		SharedPartyUpdater(P, msg, errCh)
+	}
+}
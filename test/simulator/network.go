@@ -0,0 +1,311 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package simulator runs parties over an in-memory network that delays, reorders, duplicates and drops their
+// messages. The parties are updated one message at a time in an order drawn from a seed, so that a run with the same
+// seed delivers the same messages in the same order.
+package simulator
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// ErrStalled is returned by Run when no message is left to deliver but some of the parties have not finished
+var ErrStalled = errors.New("simulator: the parties stalled")
+
+type (
+	// Config tells the network how to deliver the messages
+	Config struct {
+		// Seed of the random choices of the network; the same seed gives the same schedule
+		Seed int64
+		// Latency is the time that every message takes to arrive
+		Latency time.Duration
+		// Jitter is the most extra time that a message may take on top of Latency; a non-zero Jitter reorders messages
+		Jitter time.Duration
+		// DuplicateRate is the probability that a message is delivered twice
+		DuplicateRate float64
+		// DropRate is the probability that a message is never delivered
+		DropRate float64
+		// RoundTimeout is the most virtual time that a party may wait in a round; zero means no limit
+		RoundTimeout time.Duration
+	}
+
+	// Stats counts what happened to the messages of a run
+	Stats struct {
+		Sent       int // messages output by the parties, a broadcast counts once for each receiver
+		Delivered  int
+		Duplicated int
+		Dropped    int
+		// Elapsed is the virtual time when the last message was delivered
+		Elapsed time.Duration
+	}
+
+	// Network delivers the messages of the parties that output them to Out
+	Network struct {
+		cfg   Config
+		rnd   *rand.Rand
+		out   chan tss.Message
+		flush chan chan []tss.Message
+		stop  chan struct{}
+
+		parties map[string]tss.Party
+		rounds  map[string]round
+		queue   deliveries
+		seq     uint64
+		now     time.Duration
+		stats   Stats
+	}
+
+	delivery struct {
+		at  time.Duration
+		seq uint64 // breaks the ties of at in the order the deliveries were scheduled
+		to  tss.Party
+		msg tss.Message
+	}
+
+	deliveries []*delivery
+
+	// round is the round that a party was last seen in, and the virtual time that it entered it
+	round struct {
+		name  string
+		since time.Duration
+	}
+)
+
+func NewNetwork(cfg Config) *Network {
+	return &Network{
+		cfg:     cfg,
+		rnd:     rand.New(rand.NewSource(cfg.Seed)),
+		out:     make(chan tss.Message),
+		flush:   make(chan chan []tss.Message),
+		stop:    make(chan struct{}),
+		parties: make(map[string]tss.Party),
+		rounds:  make(map[string]round),
+	}
+}
+
+// Simulate creates `count` parties with `newParty`, which passes `out` to the constructor of the party, and runs them
+// over a network configured by `cfg`. The end channel of the parties must have room for the result of every party.
+func Simulate(cfg Config, count int, newParty func(i int, out chan<- tss.Message) tss.Party) (Stats, error) {
+	net := NewNetwork(cfg)
+	parties := make([]tss.Party, count)
+	for i := range parties {
+		parties[i] = newParty(i, net.Out())
+	}
+	return net.Run(parties...)
+}
+
+// Out is the channel to pass as the out channel of the parties
+func (net *Network) Out() chan<- tss.Message {
+	return net.out
+}
+
+// Run starts the parties and delivers their messages until none is left. A party is sent the messages addressed to
+// its PartyID, by key, so the old and the new committee of a resharing may run on the same network. Run returns the
+// first error of a party, or ErrStalled when some parties are still running at the end, e.g. after a message was
+// dropped. With a RoundTimeout, a party that waits longer than it in a round of virtual time fails instead with a
+// *tss.Error wrapping tss.ErrRoundTimeout, which blames the parties it was waiting for. A network runs once.
+func (net *Network) Run(parties ...tss.Party) (Stats, error) {
+	for _, P := range parties {
+		net.parties[partyKey(P.PartyID())] = P
+	}
+	go net.collect()
+	defer close(net.stop)
+
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return net.stats, err
+		}
+		net.watch(P)
+		net.schedule(net.collected())
+	}
+	for {
+		if err := net.timeout(parties); err != nil {
+			return net.stats, err
+		}
+		if net.queue.Len() == 0 {
+			break
+		}
+		d := heap.Pop(&net.queue).(*delivery)
+		net.now = d.at
+		bz, routing, err := d.msg.WireBytes()
+		if err != nil {
+			return net.stats, d.to.WrapError(err)
+		}
+		pMsg, err := tss.ParseWireMessageWithRouting(bz, routing)
+		if err != nil {
+			return net.stats, d.to.WrapError(err)
+		}
+		net.stats.Delivered++
+		net.stats.Elapsed = net.now
+		if _, err := d.to.Update(pMsg); err != nil {
+			return net.stats, err
+		}
+		net.watch(d.to)
+		net.schedule(net.collected())
+	}
+	for _, P := range parties {
+		if P.Running() {
+			return net.stats, fmt.Errorf("%w: party %s is waiting for %v", ErrStalled, P.PartyID(), P.WaitingFor())
+		}
+	}
+	return net.stats, nil
+}
+
+// watch notes the virtual time when `P` entered its current round
+func (net *Network) watch(P tss.Party) {
+	key := partyKey(P.PartyID())
+	if name := P.String(); net.rounds[key].name != name {
+		net.rounds[key] = round{name: name, since: net.now}
+	}
+}
+
+// timeout fails the first party whose round times out before the next message arrives, or before the end of the run
+// when no message is left. A message that arrives at the deadline is still in time.
+func (net *Network) timeout(parties []tss.Party) *tss.Error {
+	if net.cfg.RoundTimeout <= 0 {
+		return nil
+	}
+	var late tss.Party
+	var deadline time.Duration
+	for _, P := range parties {
+		if !P.Running() {
+			continue
+		}
+		if at := net.rounds[partyKey(P.PartyID())].since + net.cfg.RoundTimeout; late == nil || at < deadline {
+			late, deadline = P, at
+		}
+	}
+	if late == nil || (net.queue.Len() > 0 && net.queue[0].at <= deadline) {
+		return nil
+	}
+	net.now = deadline
+	culprits := make([]*tss.PartyID, 0)
+	for _, Pj := range late.WaitingFor() {
+		if partyKey(Pj) != partyKey(late.PartyID()) {
+			culprits = append(culprits, Pj)
+		}
+	}
+	return late.WrapError(tss.ErrRoundTimeout, culprits...)
+}
+
+// collect receives the messages output by the parties. As `out` is unbuffered, every message output by a call to
+// Start or Update has been received once the call returns, so the following flush returns all of them.
+func (net *Network) collect() {
+	var pending []tss.Message
+	for {
+		select {
+		case msg := <-net.out:
+			pending = append(pending, msg)
+		case reply := <-net.flush:
+			reply <- pending
+			pending = nil
+		case <-net.stop:
+			return
+		}
+	}
+}
+
+func (net *Network) collected() []tss.Message {
+	reply := make(chan []tss.Message)
+	net.flush <- reply
+	return <-reply
+}
+
+// schedule draws the fate of every copy of `msgs` to a receiver. The rounds may output their messages concurrently,
+// so they are sorted first to make the draws depend on the seed alone.
+func (net *Network) schedule(msgs []tss.Message) {
+	sort.SliceStable(msgs, func(a, b int) bool {
+		if msgs[a].Type() != msgs[b].Type() {
+			return msgs[a].Type() < msgs[b].Type()
+		}
+		return firstReceiver(msgs[a]) < firstReceiver(msgs[b])
+	})
+	for _, msg := range msgs {
+		for _, to := range net.receivers(msg) {
+			net.stats.Sent++
+			if net.rnd.Float64() < net.cfg.DropRate {
+				net.stats.Dropped++
+				continue
+			}
+			net.push(to, msg)
+			if net.rnd.Float64() < net.cfg.DuplicateRate {
+				net.stats.Duplicated++
+				net.push(to, msg)
+			}
+		}
+	}
+}
+
+func (net *Network) push(to tss.Party, msg tss.Message) {
+	at := net.now + net.cfg.Latency
+	if net.cfg.Jitter > 0 {
+		at += time.Duration(net.rnd.Int63n(int64(net.cfg.Jitter) + 1))
+	}
+	net.seq++
+	heap.Push(&net.queue, &delivery{at: at, seq: net.seq, to: to, msg: msg})
+}
+
+// receivers are the parties that `msg` is addressed to; a broadcast goes to every party on the network but its sender
+func (net *Network) receivers(msg tss.Message) []tss.Party {
+	var to []tss.Party
+	if dest := msg.GetTo(); dest != nil {
+		for _, Pj := range dest {
+			if P, ok := net.parties[partyKey(Pj)]; ok && partyKey(Pj) != partyKey(msg.GetFrom()) {
+				to = append(to, P)
+			}
+		}
+		return to
+	}
+	for _, P := range net.parties {
+		if partyKey(P.PartyID()) != partyKey(msg.GetFrom()) {
+			to = append(to, P)
+		}
+	}
+	// map iteration is random
+	sort.Slice(to, func(a, b int) bool {
+		return partyKey(to[a].PartyID()) < partyKey(to[b].PartyID())
+	})
+	return to
+}
+
+func firstReceiver(msg tss.Message) string {
+	if dest := msg.GetTo(); len(dest) > 0 {
+		return partyKey(dest[0])
+	}
+	return ""
+}
+
+func partyKey(pID *tss.PartyID) string {
+	return string(pID.GetKey())
+}
+
+func (q deliveries) Len() int { return len(q) }
+
+func (q deliveries) Less(a, b int) bool {
+	if q[a].at != q[b].at {
+		return q[a].at < q[b].at
+	}
+	return q[a].seq < q[b].seq
+}
+
+func (q deliveries) Swap(a, b int) { q[a], q[b] = q[b], q[a] }
+
+func (q *deliveries) Push(x interface{}) { *q = append(*q, x.(*delivery)) }
+
+func (q *deliveries) Pop() interface{} {
+	old := *q
+	d := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return d
+}
@@ -0,0 +1,190 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	testParticipants = 5
+	testThreshold    = 2
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+// recorder is a party that notes every message that it is given
+type recorder struct {
+	tss.Party
+	log *[]string
+}
+
+func (r recorder) Update(msg tss.ParsedMessage) (bool, *tss.Error) {
+	*r.log = append(*r.log, fmt.Sprintf("%s from %s to %s", msg.Type(), msg.GetFrom(), r.PartyID()))
+	return r.Party.Update(msg)
+}
+
+// runKeygen runs an EdDSA keygen over a network configured by `cfg`, and returns the messages in the order they were
+// delivered along with the keys of the parties that finished
+func runKeygen(cfg Config) (Stats, []string, []*keygen.LocalPartySaveData, error) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	var delivered []string
+	stats, err := Simulate(cfg, len(pIDs), func(i int, out chan<- tss.Message) tss.Party {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		return recorder{keygen.NewLocalParty(params, out, endCh), &delivered}
+	})
+	close(endCh)
+	var saves []*keygen.LocalPartySaveData
+	for save := range endCh {
+		saves = append(saves, save)
+	}
+	return stats, delivered, saves, err
+}
+
+func TestSimulatedNetwork(t *testing.T) {
+	setUp("error")
+
+	// the same seed gives the same schedule
+	cfg := Config{Seed: 42, Latency: time.Millisecond, Jitter: 20 * time.Millisecond, DuplicateRate: 0.3}
+	stats, delivered, saves, err := runKeygen(cfg)
+	assert.NoError(t, err)
+	if assert.Len(t, saves, testParticipants) {
+		for _, save := range saves {
+			assert.True(t, save.EDDSAPub.Equals(saves[0].EDDSAPub))
+		}
+	}
+	againStats, again, _, err := runKeygen(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, againStats)
+	assert.Equal(t, delivered, again)
+}
+
+func TestDuplicatedMessages(t *testing.T) {
+	setUp("error")
+
+	// every message arrives twice, and the parties ignore the second copy
+	stats, delivered, saves, err := runKeygen(Config{Seed: 1, DuplicateRate: 1})
+	assert.NoError(t, err)
+	assert.Len(t, saves, testParticipants)
+	assert.Equal(t, stats.Sent, stats.Duplicated)
+	assert.Equal(t, 2*stats.Sent, stats.Delivered)
+	assert.Len(t, delivered, stats.Delivered)
+}
+
+func TestDroppedMessages(t *testing.T) {
+	setUp("error")
+
+	// a dropped message leaves its receiver waiting for the sender
+	stats, _, saves, err := runKeygen(Config{Seed: 42, DropRate: 0.1})
+	assert.ErrorIs(t, err, ErrStalled)
+	assert.Greater(t, stats.Dropped, 0)
+	assert.Equal(t, stats.Sent-stats.Dropped, stats.Delivered)
+	assert.Less(t, len(saves), testParticipants)
+
+	// with a round timeout, the receiver blames the sender instead
+	stats, _, _, err = runKeygen(Config{Seed: 42, Latency: time.Millisecond, DropRate: 0.1, RoundTimeout: time.Second})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, tss.ErrRoundTimeout)
+		tssErr, ok := err.(*tss.Error)
+		if assert.True(t, ok) {
+			assert.Equal(t, tss.KindTimeout, tssErr.Kind())
+			assert.NotEmpty(t, tssErr.Culprits())
+			assert.NotContains(t, tssErr.Culprits(), tssErr.Victim())
+		}
+	}
+	assert.Greater(t, stats.Dropped, 0)
+}
+
+func TestJitterReordersMessages(t *testing.T) {
+	setUp("error")
+
+	// without jitter the messages arrive in the order they were sent, one latency per round
+	stats, inOrder, _, err := runKeygen(Config{Seed: 1, Latency: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Millisecond, stats.Elapsed)
+	again, _, _, err := runKeygen(Config{Seed: 2, Latency: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.Equal(t, stats, again)
+
+	// with jitter the same messages arrive in another order, which depends on the seed
+	stats, reordered, saves, err := runKeygen(Config{Seed: 1, Latency: 10 * time.Millisecond, Jitter: 50 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.Len(t, saves, testParticipants)
+	assert.Greater(t, stats.Elapsed, 20*time.Millisecond)
+	assert.ElementsMatch(t, inOrder, reordered)
+	assert.NotEqual(t, inOrder, reordered)
+	_, otherSeed, _, err := runKeygen(Config{Seed: 2, Latency: 10 * time.Millisecond, Jitter: 50 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, reordered, otherSeed)
+	assert.NotEqual(t, reordered, otherSeed)
+}
+
+func TestRoundTimeoutInVirtualTime(t *testing.T) {
+	setUp("error")
+
+	// a message that arrives at the deadline is in time
+	_, _, saves, err := runKeygen(Config{Seed: 1, Latency: time.Hour, RoundTimeout: time.Hour})
+	assert.NoError(t, err)
+	assert.Len(t, saves, testParticipants)
+
+	// the messages of round 1 arrive after the round timed out, although no real time passed
+	stats, _, _, err := runKeygen(Config{Seed: 1, Latency: time.Hour, RoundTimeout: time.Minute})
+	assert.ErrorIs(t, err, tss.ErrRoundTimeout)
+	assert.Zero(t, stats.Delivered)
+	if tssErr, ok := err.(*tss.Error); assert.True(t, ok) {
+		assert.Equal(t, 1, tssErr.Round())
+		assert.Len(t, tssErr.Culprits(), testParticipants-1)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	deliverAll := func(cfg Config) (Stats, []int) {
+		relay := NewRelay(cfg)
+		var mtx sync.Mutex
+		var got []int
+		for i := 0; i < 20; i++ {
+			relay.Deliver(func() {
+				mtx.Lock()
+				defer mtx.Unlock()
+				got = append(got, i)
+			})
+		}
+		return relay.Flush(), got
+	}
+
+	stats, got := deliverAll(Config{Seed: 1, DuplicateRate: 1})
+	assert.Equal(t, Stats{Sent: 20, Delivered: 40, Duplicated: 20}, stats)
+	assert.Len(t, got, 40)
+
+	stats, got = deliverAll(Config{Seed: 1, DropRate: 1})
+	assert.Equal(t, Stats{Sent: 20, Dropped: 20}, stats)
+	assert.Empty(t, got)
+
+	// the messages are delivered after the latency, in another order than they were sent
+	start := time.Now()
+	stats, got = deliverAll(Config{Seed: 1, Latency: 10 * time.Millisecond, Jitter: 100 * time.Millisecond})
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Equal(t, 20, stats.Delivered)
+	assert.Len(t, got, 20)
+	assert.False(t, sort.IntsAreSorted(got))
+}
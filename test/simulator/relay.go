@@ -0,0 +1,76 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package simulator
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Relay delivers the messages of parties that run on their own, e.g. those of package implement, with the faults of a
+// Config. Unlike Run, a relay cannot tell when a party is done with a message, so Latency and Jitter are real time and
+// RoundTimeout is ignored; the order of the deliveries depends on the scheduler as well as on the seed.
+type Relay struct {
+	cfg Config
+
+	mtx     sync.Mutex
+	rnd     *rand.Rand
+	stats   Stats
+	pending int
+	idle    *sync.Cond
+}
+
+func NewRelay(cfg Config) *Relay {
+	r := &Relay{cfg: cfg, rnd: rand.New(rand.NewSource(cfg.Seed))}
+	r.idle = sync.NewCond(&r.mtx)
+	return r
+}
+
+// Deliver calls `deliver` once for a message sent to a receiver, twice when the message is duplicated or never when
+// it is dropped, each time after the latency and a jitter
+func (r *Relay) Deliver(deliver func()) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.stats.Sent++
+	if r.rnd.Float64() < r.cfg.DropRate {
+		r.stats.Dropped++
+		return
+	}
+	r.push(deliver)
+	if r.rnd.Float64() < r.cfg.DuplicateRate {
+		r.stats.Duplicated++
+		r.push(deliver)
+	}
+}
+
+func (r *Relay) push(deliver func()) {
+	delay := r.cfg.Latency
+	if r.cfg.Jitter > 0 {
+		delay += time.Duration(r.rnd.Int63n(int64(r.cfg.Jitter) + 1))
+	}
+	r.pending++
+	time.AfterFunc(delay, func() {
+		deliver()
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		r.stats.Delivered++
+		if r.pending--; r.pending == 0 {
+			r.idle.Broadcast()
+		}
+	})
+}
+
+// Flush waits until every message that is not dropped has been delivered, e.g. before the receivers are closed
+func (r *Relay) Flush() Stats {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for r.pending > 0 {
+		r.idle.Wait()
+	}
+	return r.stats
+}
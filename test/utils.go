@@ -15,12 +15,12 @@ func SharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Erro
 	if party.PartyID() == msg.GetFrom() {
 		return
 	}
-	bz, _, err := msg.WireBytes()
+	bz, routing, err := msg.WireBytes()
 	if err != nil {
 		errCh <- party.WrapError(err)
 		return
 	}
-	pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+	pMsg, err := tss.ParseWireMessageWithRouting(bz, routing)
 	if err != nil {
 		errCh <- party.WrapError(err)
 		return
@@ -0,0 +1,136 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+type (
+	// BlameReport is the JSON form of an Error that blames culprits. The parties of a failed session can exchange
+	// their reports and use AgreedCulprits to find the parties that enough of them blamed.
+	BlameReport struct {
+		SessionID []byte           `json:"session_id,omitempty"`
+		Task      string           `json:"task"`
+		Round     int              `json:"round"`
+		Kind      ErrorKind        `json:"kind"`
+		Proof     ProofType        `json:"proof,omitempty"`
+		Reason    string           `json:"reason"`
+		Reporter  *BlamedPartyID   `json:"reporter"`
+		Culprits  []*BlamedPartyID `json:"culprits"`
+	}
+
+	// BlamedPartyID identifies a party in a BlameReport
+	BlamedPartyID struct {
+		ID      string `json:"id"`
+		Moniker string `json:"moniker,omitempty"`
+		Key     []byte `json:"key"`
+		Index   int    `json:"index"`
+	}
+)
+
+// BlameReport returns the report of this error made by its victim in the session `sessionID`, which may be nil
+func (err *Error) BlameReport(sessionID []byte) *BlameReport {
+	report := &BlameReport{
+		SessionID: sessionID,
+		Task:      err.task,
+		Round:     err.round,
+		Kind:      err.Kind(),
+		Proof:     err.Proof(),
+		Culprits:  make([]*BlamedPartyID, 0, len(err.culprits)),
+	}
+	if err.cause != nil {
+		report.Reason = err.cause.Error()
+	}
+	if err.victim != nil {
+		report.Reporter = newBlamedPartyID(err.victim)
+	}
+	for _, culprit := range err.culprits {
+		if culprit != nil {
+			report.Culprits = append(report.Culprits, newBlamedPartyID(culprit))
+		}
+	}
+	return report
+}
+
+// ParseBlameReport decodes a report sent by another party and checks that it is well formed
+func ParseBlameReport(bz []byte) (*BlameReport, error) {
+	report := new(BlameReport)
+	if err := json.Unmarshal(bz, report); err != nil {
+		return nil, err
+	}
+	if !report.ValidateBasic() {
+		return nil, errors.New("ParseBlameReport: the report is malformed")
+	}
+	return report, nil
+}
+
+func (report *BlameReport) ValidateBasic() bool {
+	if report == nil || report.Task == "" || report.Kind == "" || !report.Reporter.validateBasic() {
+		return false
+	}
+	for _, culprit := range report.Culprits {
+		if !culprit.validateBasic() {
+			return false
+		}
+	}
+	return true
+}
+
+// AgreedCulprits returns the parties that were blamed by at least `quorum` distinct reporters, sorted by key.
+// `reports` holds the report of each party keyed by the sender that the transport authenticated, since the Reporter
+// in a report is only claimed by it; a report whose Reporter is not its sender is ignored, as is a party blaming itself.
+func AgreedCulprits(reports map[*PartyID]*BlameReport, quorum int) []*PartyID {
+	blamers := make(map[string]map[string]struct{}) // culprit key -> reporter keys
+	culprits := make(map[string]*BlamedPartyID)
+	for sender, report := range reports {
+		if sender == nil || !report.ValidateBasic() {
+			continue
+		}
+		reporter := string(sender.GetKey())
+		if string(report.Reporter.Key) != reporter {
+			continue
+		}
+		for _, culprit := range report.Culprits {
+			key := string(culprit.Key)
+			if key == reporter {
+				continue
+			}
+			if blamers[key] == nil {
+				blamers[key] = make(map[string]struct{})
+				culprits[key] = culprit
+			}
+			blamers[key][reporter] = struct{}{}
+		}
+	}
+	agreed := make([]*PartyID, 0, len(culprits))
+	for key, culprit := range culprits {
+		if quorum <= len(blamers[key]) {
+			agreed = append(agreed, culprit.PartyID())
+		}
+	}
+	sort.Slice(agreed, func(a, b int) bool { return agreed[a].KeyInt().Cmp(agreed[b].KeyInt()) < 0 })
+	return agreed
+}
+
+func newBlamedPartyID(id *PartyID) *BlamedPartyID {
+	return &BlamedPartyID{ID: id.GetId(), Moniker: id.GetMoniker(), Key: id.GetKey(), Index: id.Index}
+}
+
+// PartyID rebuilds the PartyID of a blamed party, including its index
+func (id *BlamedPartyID) PartyID() *PartyID {
+	pID := NewPartyID(id.ID, id.Moniker, new(big.Int).SetBytes(id.Key))
+	pID.Index = id.Index
+	return pID
+}
+
+func (id *BlamedPartyID) validateBasic() bool {
+	return id != nil && 0 < len(id.Key) && 0 <= id.Index
+}
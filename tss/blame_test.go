@@ -0,0 +1,100 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestBadShareIsBlamedAsInvalidProof(t *testing.T) {
+	session := newKeygenSession(nil)
+	pIDs := session.PartyIDs
+	n := len(pIDs)
+	outCh := make(chan tss.Message, n*n*3)
+	endCh := make(chan *keygen.LocalPartySaveData, n)
+	errCh := make(chan *tss.Error, n*n*3)
+	parties := newKeygenParties(session, outCh, endCh)
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// party 0 sends every other party a share that does not match its commitments
+	reports := make(map[*tss.PartyID]*tss.BlameReport, n-1)
+	for len(reports) < n-1 {
+		select {
+		case err := <-errCh:
+			assert.Equal(t, tss.KindInvalidProof, err.Kind())
+			assert.Equal(t, tss.ProofVSS, err.Proof())
+			assert.True(t, errors.Is(err, tss.ErrInvalidProof))
+			assert.False(t, errors.Is(err, tss.ErrInternal))
+			var proofErr *tss.ProofError
+			if assert.True(t, errors.As(err, &proofErr)) {
+				assert.Equal(t, tss.ProofVSS, proofErr.Proof)
+			}
+
+			// the reports survive the round trip through JSON
+			bz, jErr := json.Marshal(err.BlameReport(nil))
+			assert.NoError(t, jErr)
+			report, jErr := tss.ParseBlameReport(bz)
+			assert.NoError(t, jErr)
+			assert.Equal(t, tss.KindInvalidProof, report.Kind)
+			assert.Equal(t, err.Round(), report.Round)
+			reports[err.Victim()] = report
+		case msg := <-outCh:
+			if r2msg1, ok := msg.(tss.ParsedMessage).Content().(*keygen.KGRound2Message1); ok && msg.GetFrom().Index == 0 {
+				share := new(big.Int).Add(r2msg1.UnmarshalShare(), big.NewInt(1))
+				msg = keygen.NewKGRound2Message1(msg.GetTo()[0], msg.GetFrom(), &vss.Share{Share: share})
+			}
+			test.RouteMessage(parties, msg, errCh)
+		case save := <-endCh:
+			// only party 0 received good shares from everyone
+			assert.Equal(t, pIDs[0].KeyInt(), save.ShareID)
+		}
+	}
+
+	agreed := tss.AgreedCulprits(reports, n-1)
+	if assert.Len(t, agreed, 1) {
+		assert.Equal(t, pIDs[0].KeyInt(), agreed[0].KeyInt())
+		assert.Equal(t, 0, agreed[0].Index)
+	}
+	assert.Empty(t, tss.AgreedCulprits(reports, n))
+}
+
+func TestAgreedCulpritsCountsAuthenticatedSenders(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	n := len(pIDs)
+	blame := func(reporter, culprit *tss.PartyID) *tss.BlameReport {
+		err := tss.NewError(errors.New("bad share"), "eddsa-keygen", 2, reporter, culprit)
+		return err.BlameReport(nil)
+	}
+
+	// every party but party 1 blames it
+	reports := make(map[*tss.PartyID]*tss.BlameReport, n)
+	reports[pIDs[0]] = blame(pIDs[0], pIDs[1])
+	for _, pID := range pIDs[2:] {
+		reports[pID] = blame(pID, pIDs[1])
+	}
+	assert.Len(t, tss.AgreedCulprits(reports, n-1), 1)
+	// a report sent by party 0 under the name of another party is not counted
+	forged := map[*tss.PartyID]*tss.BlameReport{pIDs[0]: reports[pIDs[2]]}
+	assert.Empty(t, tss.AgreedCulprits(forged, 1))
+
+	// and no party can blame itself
+	assert.Empty(t, tss.AgreedCulprits(map[*tss.PartyID]*tss.BlameReport{pIDs[1]: blame(pIDs[1], pIDs[1])}, 1))
+}
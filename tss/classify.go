@@ -0,0 +1,147 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	ProtocolKeygen    Protocol = "keygen"
+	ProtocolSigning   Protocol = "signing"
+	ProtocolResharing Protocol = "resharing"
+
+	FamilyECDSA CurveFamily = "ecdsa"
+	FamilyEdDSA CurveFamily = "eddsa"
+
+	messagePackage = "binance.tsslib"
+	typeURLPrefix  = "type.googleapis.com/"
+)
+
+// ErrUnknownMessage is returned by ClassifyMessage for a message type that is not a message of this library
+var ErrUnknownMessage = errors.New("unknown message type")
+
+type (
+	// Protocol is the protocol that a message belongs to
+	Protocol string
+
+	// CurveFamily is the signature scheme of the protocol that a message belongs to
+	CurveFamily string
+
+	// MessageClass describes a type of message, as declared by the options of its proto descriptor.
+	// The class only tells the type of a message apart: presigning and online signing send the messages of rounds 1 to
+	// 4 and 5 to 9 of signing, and batch signing carries them in a SignBatchMessage, so they are all classified as
+	// signing. A transport that must tell these sessions apart does so by their session id.
+	MessageClass struct {
+		TypeURL string
+		// Protocol and Family are empty for the messages that belong to no protocol, such as VersionHello
+		Protocol Protocol
+		Family   CurveFamily
+		// Round is 0 for the messages sent before round 1 and for the ones sent in any round, such as those of batch
+		// signing and the echoes of broadcast.EchoParty
+		Round     int
+		Routing   Routing
+		Committee Committee
+	}
+)
+
+var (
+	messageClasses     map[string]*MessageClass
+	messageClassesOnce sync.Once
+)
+
+// ClassifyMessage describes the message in `wireBytes`, as returned by Message.WireBytes, without parsing its content.
+// Transports may use it to route and prioritise messages before they reach a party.
+func ClassifyMessage(wireBytes []byte) (*MessageClass, error) {
+	msg := new(anypb.Any)
+	if err := proto.Unmarshal(wireBytes, msg); err != nil {
+		return nil, err
+	}
+	return ClassifyTypeURL(msg.GetTypeUrl())
+}
+
+// ClassifyTypeURL describes the message type of the type URL of a google.protobuf.Any
+func ClassifyTypeURL(typeURL string) (*MessageClass, error) {
+	messageClassesOnce.Do(loadMessageClasses)
+	class, ok := messageClasses[typeURL]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMessage, typeURL)
+	}
+	c := *class
+	return &c, nil
+}
+
+// IsBroadcast reports whether messages of the class are always broadcast to their committee. It is false for the
+// messages of Routing_BROADCAST_OR_P2P, such as SignBatchMessage, which may be either; use IsBroadcastWith for them.
+func (class *MessageClass) IsBroadcast() bool {
+	return class.Routing == Routing_BROADCAST
+}
+
+// IsBroadcastWith reports whether a message of the class that was sent with `routing`, as returned by
+// Message.WireBytes, is broadcast to its committee. Transports should route every message with it, as the routing of
+// the messages of Routing_BROADCAST_OR_P2P is only known from their MessageRouting.
+func (class *MessageClass) IsBroadcastWith(routing *MessageRouting) bool {
+	if class.Routing == Routing_BROADCAST_OR_P2P {
+		return routing != nil && routing.IsBroadcast
+	}
+	return class.IsBroadcast()
+}
+
+func (class *MessageClass) String() string {
+	return fmt.Sprintf("%s %s %s round %d, %s to %s", class.TypeURL, class.Family, class.Protocol, class.Round,
+		class.Routing, class.Committee)
+}
+
+// loadMessageClasses reads the class of every message of the library that was linked into the binary from the
+// descriptor of its file: the protocol and family from the proto package, e.g. binance.tsslib.ecdsa.signing, and the
+// round, routing and committee from the options of the message.
+func loadMessageClasses() {
+	messageClasses = make(map[string]*MessageClass)
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		pkg := string(fd.Package())
+		if pkg != messagePackage && !strings.HasPrefix(pkg, messagePackage+".") {
+			return true
+		}
+		var (
+			family   CurveFamily
+			protocol Protocol
+		)
+		if sub := strings.TrimPrefix(pkg, messagePackage+"."); sub != pkg {
+			if parts := strings.Split(sub, "."); len(parts) == 2 {
+				family, protocol = CurveFamily(parts[0]), Protocol(parts[1])
+			}
+		}
+		msgs := fd.Messages()
+		for i := 0; i < msgs.Len(); i++ {
+			md := msgs.Get(i)
+			opts := md.Options()
+			routing := proto.GetExtension(opts, E_Routing).(Routing)
+			// messages without a routing are not sent on their own, e.g. MessageWrapper
+			if routing == Routing_ROUTING_UNSPECIFIED {
+				continue
+			}
+			typeURL := typeURLPrefix + string(md.FullName())
+			messageClasses[typeURL] = &MessageClass{
+				TypeURL:   typeURL,
+				Protocol:  protocol,
+				Family:    family,
+				Round:     int(proto.GetExtension(opts, E_Round).(uint32)),
+				Routing:   routing,
+				Committee: proto.GetExtension(opts, E_Committee).(Committee),
+			}
+		}
+		return true
+	})
+}
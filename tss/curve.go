@@ -9,7 +9,7 @@ package tss
 import (
 	"crypto/elliptic"
 	"errors"
-	"reflect"
+	"math/big"
 
 	s256k1 "github.com/btcsuite/btcd/btcec/v2"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
@@ -20,6 +20,7 @@ type CurveName string
 const (
 	Secp256k1 CurveName = "secp256k1"
 	Ed25519   CurveName = "ed25519"
+	NISTP256  CurveName = "P-256"
 )
 
 var (
@@ -34,6 +35,7 @@ func init() {
 	registry = make(map[CurveName]elliptic.Curve)
 	registry[Secp256k1] = s256k1.S256()
 	registry[Ed25519] = edwards.Edwards()
+	registry[NISTP256] = elliptic.P256()
 }
 
 func RegisterCurve(name CurveName, curve elliptic.Curve) {
@@ -50,9 +52,13 @@ func GetCurveByName(name CurveName) (elliptic.Curve, bool) {
 }
 
 // return name, exist(bool)
+// the curves of the standard library share a type, so a curve is matched by its domain parameters
 func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
+	if curve == nil {
+		return "", false
+	}
 	for name, e := range registry {
-		if reflect.TypeOf(curve) == reflect.TypeOf(e) {
+		if sameCurveParams(curve.Params(), e.Params()) {
 			return name, true
 		}
 	}
@@ -60,6 +66,50 @@ func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
 	return "", false
 }
 
+func sameCurveParams(lhs, rhs *elliptic.CurveParams) bool {
+	if lhs == nil || rhs == nil {
+		return false
+	}
+	if lhs == rhs {
+		return true
+	}
+	for _, pair := range [][2]*big.Int{{lhs.P, rhs.P}, {lhs.N, rhs.N}, {lhs.B, rhs.B}, {lhs.Gx, rhs.Gx}, {lhs.Gy, rhs.Gy}} {
+		// some curves, e.g. edwards, leave B unset
+		if pair[0] == nil || pair[1] == nil {
+			if pair[0] != pair[1] {
+				return false
+			}
+			continue
+		}
+		if pair[0].Cmp(pair[1]) != 0 {
+			return false
+		}
+	}
+	return lhs.BitSize == rhs.BitSize
+}
+
+// MaxCurveBitLen returns the largest CurveBitLen of the registered curves.
+// It bounds the scalars and point coordinates that a party may receive, whichever curve the party is on.
+func MaxCurveBitLen() int {
+	maxBits := 0
+	for _, e := range registry {
+		if bits := CurveBitLen(e); maxBits < bits {
+			maxBits = bits
+		}
+	}
+	return maxBits
+}
+
+// CurveBitLen returns the larger of the bit lengths of the order and the field of `ec`, which bounds its scalars
+// and the coordinates of its points
+func CurveBitLen(ec elliptic.Curve) int {
+	params := ec.Params()
+	if params.P.BitLen() < params.N.BitLen() {
+		return params.N.BitLen()
+	}
+	return params.P.BitLen()
+}
+
 // SameCurve returns true if both lhs and rhs are the same known curve
 func SameCurve(lhs, rhs elliptic.Curve) bool {
 	lName, lOk := GetCurveName(lhs)
@@ -90,6 +140,11 @@ func S256() elliptic.Curve {
 	return s256k1.S256()
 }
 
+// NIST P-256 (secp256r1)
+func P256() elliptic.Curve {
+	return elliptic.P256()
+}
+
 func Edwards() elliptic.Curve {
 	return edwards.Edwards()
 }
@@ -7,18 +7,81 @@
 package tss
 
 import (
+	"context"
+	"errors"
 	"fmt"
 )
 
-// fundamental is an error that has a message and a stack, but no caller.
-type Error struct {
-	cause    error
-	task     string
-	round    int
-	victim   *PartyID
-	culprits []*PartyID
+// ErrRoundTimeout is the cause of the error reported when a round exceeds the round timeout set on the Parameters
+var ErrRoundTimeout = errors.New("round timed out waiting for messages")
+
+// ErrEquivocation is the cause of the error reported when a party sends two different messages for the same round
+var ErrEquivocation = errors.New("equivocation")
+
+var (
+	// ErrInvalidProof matches, with errors.Is, an error raised because a proof from a culprit failed to verify.
+	// Use errors.As with a *ProofError to learn which proof it was.
+	ErrInvalidProof = errors.New("invalid proof")
+	// ErrInvalidMessage matches an error raised because a culprit sent a malformed or unexpected message
+	ErrInvalidMessage = errors.New("invalid message")
+	// ErrInternal matches an error that does not blame any other party, e.g. a failure of this party's own computation
+	ErrInternal = errors.New("internal error")
+)
+
+// ErrorKind classifies an Error for programs that handle the failures of a party, see Error.Kind
+type ErrorKind string
+
+const (
+	KindInvalidProof   ErrorKind = "invalid-proof"
+	KindInvalidMessage ErrorKind = "invalid-message"
+	KindTimeout        ErrorKind = "timeout"
+	KindCanceled       ErrorKind = "canceled"
+	KindEquivocation   ErrorKind = "equivocation"
+	KindInternal       ErrorKind = "internal"
+)
+
+type (
+	// fundamental is an error that has a message and a stack, but no caller.
+	Error struct {
+		cause    error
+		task     string
+		round    int
+		victim   *PartyID
+		culprits []*PartyID
+	}
+
+	// ProofError is the cause of an Error raised because a proof of type Proof sent by the culprits failed to verify
+	ProofError struct {
+		Proof ProofType
+		cause error
+	}
+)
+
+func NewProofError(proof ProofType, cause error) *ProofError {
+	return &ProofError{Proof: proof, cause: cause}
 }
 
+func (err *ProofError) Error() string { return err.cause.Error() }
+
+func (err *ProofError) Unwrap() error { return err.cause }
+
+func (err *ProofError) Is(target error) bool { return target == ErrInvalidProof }
+
+// invalidMessageError keeps the text of its cause while matching ErrInvalidMessage
+type invalidMessageError struct {
+	cause error
+}
+
+func invalidMessage(cause error) error { return &invalidMessageError{cause} }
+
+func (err *invalidMessageError) Error() string { return err.cause.Error() }
+
+func (err *invalidMessageError) Unwrap() error { return err.cause }
+
+func (err *invalidMessageError) Is(target error) bool { return target == ErrInvalidMessage }
+
+// ----- //
+
 func NewError(err error, task string, round int, victim *PartyID, culprits ...*PartyID) *Error {
 	return &Error{cause: err, task: task, round: round, victim: victim, culprits: culprits}
 }
@@ -35,6 +98,47 @@ func (err *Error) Victim() *PartyID { return err.victim }
 
 func (err *Error) Culprits() []*PartyID { return err.culprits }
 
+// Kind classifies the error by its cause. A round timeout or an expired context is a timeout, which blames the parties
+// that were waited for, and a canceled context blames no one. Any other error with culprits that has no more specific
+// cause is a message of theirs that failed a check of the round, so it is an invalid message; one without culprits is
+// internal.
+func (err *Error) Kind() ErrorKind {
+	switch {
+	case errors.Is(err.cause, ErrRoundTimeout), errors.Is(err.cause, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.Is(err.cause, context.Canceled):
+		return KindCanceled
+	case errors.Is(err.cause, ErrEquivocation):
+		return KindEquivocation
+	case errors.Is(err.cause, ErrInvalidProof):
+		return KindInvalidProof
+	case errors.Is(err.cause, ErrInvalidMessage), 0 < len(err.culprits):
+		return KindInvalidMessage
+	default:
+		return KindInternal
+	}
+}
+
+// Proof is the type of the proof that failed to verify, or "" if the error is not of KindInvalidProof
+func (err *Error) Proof() ProofType {
+	var proofErr *ProofError
+	if errors.As(err.cause, &proofErr) {
+		return proofErr.Proof
+	}
+	return ""
+}
+
+// Is makes errors.Is match the error to ErrInvalidMessage or ErrInternal by its Kind, even when the cause is untyped
+func (err *Error) Is(target error) bool {
+	switch target {
+	case ErrInvalidMessage:
+		return err.Kind() == KindInvalidMessage
+	case ErrInternal:
+		return err.Kind() == KindInternal
+	}
+	return false
+}
+
 func (err *Error) Error() string {
 	if err == nil || err.cause == nil {
 		return "Error is nil"
@@ -0,0 +1,192 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ecies"
+)
+
+// ErrInvalidSignature is the cause of the error reported for a message whose identity-key signature does not verify
+var ErrInvalidSignature = errors.New("invalid message signature")
+
+// PeerKeys holds the identity public keys of the parties, keyed by their PartyID key.
+// Ed25519 (ed25519.PublicKey) and ECDSA (*ecdsa.PublicKey) identity keys are supported.
+type PeerKeys struct {
+	mtx  sync.RWMutex
+	keys map[string]crypto.PublicKey
+}
+
+func NewPeerKeys() *PeerKeys {
+	return &PeerKeys{keys: make(map[string]crypto.PublicKey)}
+}
+
+// Add registers the identity public key of `party`, replacing any earlier one
+func (pk *PeerKeys) Add(party *PartyID, pub crypto.PublicKey) error {
+	switch pub.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+	default:
+		return fmt.Errorf("unsupported identity key type %T", pub)
+	}
+	pk.mtx.Lock()
+	defer pk.mtx.Unlock()
+	pk.keys[string(party.GetKey())] = pub
+	return nil
+}
+
+// Key returns the identity public key of `party`, if one was registered
+func (pk *PeerKeys) Key(party *PartyID) (crypto.PublicKey, bool) {
+	pk.mtx.RLock()
+	defer pk.mtx.RUnlock()
+	pub, ok := pk.keys[string(party.GetKey())]
+	return pub, ok
+}
+
+// ----- //
+
+// SignMessage signs the content and metadata of an outgoing message with the identity key of these parameters.
+// StampMessage calls it for every message of the rounds; it does nothing when no signer was set.
+func (params *Parameters) SignMessage(msg ParsedMessage) error {
+	mm, ok := msg.(*MessageImpl)
+	if !ok || params.signer == nil {
+		return nil
+	}
+	opts := crypto.SignerOpts(crypto.SHA512_256)
+	if _, ok := params.signer.Public().(ed25519.PublicKey); ok {
+		opts = crypto.Hash(0)
+	}
+	sig, err := params.signer.Sign(params.Rand(), messageDigest(mm.wire, mm.wire.GetTo()), opts)
+	if err != nil {
+		return err
+	}
+	mm.Signature = sig
+	mm.wire.Signature = sig
+	return nil
+}
+
+// VerifyMessage checks the signature of a received message against the identity key registered for its sender.
+// It accepts every message when no PeerKeys were set, and rejects unsigned messages and unknown senders otherwise.
+// A point-to-point message is only accepted by one of the recipients it was signed for; when the transport did not
+// deliver the recipients, this party is taken as the only one.
+func (params *Parameters) VerifyMessage(msg ParsedMessage) error {
+	if params.peerKeys == nil {
+		return nil
+	}
+	mm, isImpl := msg.(*MessageImpl)
+	if isImpl && mm.verifiedBy.Load() == params {
+		return nil
+	}
+	from, wire := msg.GetFrom(), msg.WireMsg()
+	pub, ok := params.peerKeys.Key(from)
+	if !ok {
+		return invalidMessage(fmt.Errorf("%w: no identity key is registered for %s", ErrInvalidSignature, from))
+	}
+	to := wire.GetTo()
+	if !wire.GetIsBroadcast() {
+		if len(to) == 0 {
+			to = []*MessageWrapper_PartyID{params.PartyID().MessageWrapper_PartyID}
+		}
+		if !containsKey(to, params.PartyID().GetKey()) {
+			return invalidMessage(fmt.Errorf("%w: from %s, which was not sent to this party", ErrInvalidSignature, from))
+		}
+	}
+	digest, sig := messageDigest(wire, to), wire.GetSignature()
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, digest, sig)
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(pub, digest, sig)
+	}
+	if !ok {
+		return invalidMessage(fmt.Errorf("%w: from %s", ErrInvalidSignature, from))
+	}
+	if isImpl {
+		mm.verifiedBy.Store(params)
+	}
+	return nil
+}
+
+// SealShare encrypts a secret share of a message of type `msgType` for `to` with its registered identity key
+func (params *Parameters) SealShare(to *PartyID, msgType string, share []byte) ([]byte, error) {
+	if params.peerKeys == nil {
+		return nil, errors.New("cannot encrypt a share without the PeerKeys")
+	}
+	pub, ok := params.peerKeys.Key(to)
+	if !ok {
+		return nil, fmt.Errorf("no identity key is registered for %s", to)
+	}
+	return ecies.Encrypt(params.Rand(), pub, share, shareAAD(params.PartyID(), to, params.SessionID(), msgType))
+}
+
+// OpenShare returns the share in a message of type `msgType` from `from`. The share is either in the clear in `share`,
+// which is refused when EncryptShares is set, or in `encryptedShare`, encrypted with SealShare to this party.
+func (params *Parameters) OpenShare(from *PartyID, msgType string, share, encryptedShare []byte) ([]byte, error) {
+	if len(encryptedShare) == 0 {
+		if params.encryptShares {
+			return nil, errors.New("received a share that was not encrypted")
+		}
+		return share, nil
+	}
+	if params.signer == nil {
+		return nil, errors.New("cannot decrypt a share without an identity key")
+	}
+	return ecies.Decrypt(params.signer, encryptedShare, shareAAD(from, params.PartyID(), params.SessionID(), msgType))
+}
+
+// shareAAD binds an encrypted share to its sender, recipient, session and message type
+func shareAAD(from, to *PartyID, sessionID []byte, msgType string) []byte {
+	return common.SHA512_256([]byte("tss-lib share"), from.GetKey(), to.GetKey(), sessionID, []byte(msgType))
+}
+
+// messageDigest binds the content of a message to its sender, session, version and whether it was broadcast, and
+// a point-to-point message to its recipients `to`, in the order of their keys. The recipients of a broadcast and the
+// resharing committee flags are not included, as UpdateFromBytes does not receive them.
+func messageDigest(wire *MessageWrapper, to []*MessageWrapper_PartyID) []byte {
+	version := make([]byte, 4)
+	binary.BigEndian.PutUint32(version, wire.GetVersion())
+	isBroadcast := []byte{0}
+	if wire.GetIsBroadcast() {
+		isBroadcast[0] = 1
+	}
+	in := [][]byte{
+		[]byte("tss-lib message"),
+		wire.GetFrom().GetKey(),
+		isBroadcast,
+		wire.GetSessionId(),
+		version,
+		[]byte(wire.GetMessage().GetTypeUrl()),
+		wire.GetMessage().GetValue(),
+	}
+	if !wire.GetIsBroadcast() {
+		keys := make([][]byte, len(to))
+		for i, Pj := range to {
+			keys[i] = Pj.GetKey()
+		}
+		sort.Slice(keys, func(a, b int) bool { return bytes.Compare(keys[a], keys[b]) < 0 })
+		in = append(in, keys...)
+	}
+	return common.SHA512_256(in...)
+}
+
+func containsKey(parties []*MessageWrapper_PartyID, key []byte) bool {
+	for _, Pj := range parties {
+		if bytes.Equal(Pj.GetKey(), key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"crypto"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestIdentitySignedMessages(t *testing.T) {
+	session := newKeygenSession(nil)
+	assert.NoError(t, session.SetIdentityKeys())
+	pIDs := session.PartyIDs
+	n := len(pIDs)
+	outCh := make(chan tss.Message, n*n*3)
+	endCh := make(chan *keygen.LocalPartySaveData, n)
+	errCh := make(chan *tss.Error, n*n*3)
+	parties := newKeygenParties(session, outCh, endCh)
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkedBroadcast, checkedP2P := false, false
+	for ended := 0; ended < n; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			bz, routing, err := msg.WireBytes()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, routing.Signature)
+			if !checkedBroadcast && msg.IsBroadcast() {
+				checkedBroadcast = true
+				P := parties[(msg.GetFrom().Index+1)%n]
+
+				// without the signature the message is rejected
+				ok, tErr := P.UpdateFromBytes(bz, msg.GetFrom(), msg.IsBroadcast())
+				assert.False(t, ok)
+				if assert.NotNil(t, tErr) {
+					assert.True(t, errors.Is(tErr, tss.ErrInvalidSignature))
+					assert.Equal(t, tss.KindInvalidMessage, tErr.Kind())
+					assert.Empty(t, tErr.Culprits())
+				}
+				// as is a message that claims to be from another party
+				forged := *routing
+				forged.From = pIDs[(msg.GetFrom().Index+2)%n]
+				ok, tErr = P.UpdateFromBytesWithRouting(bz, &forged)
+				assert.False(t, ok)
+				assert.True(t, errors.Is(tErr, tss.ErrInvalidSignature))
+			}
+			if !checkedP2P && !msg.IsBroadcast() {
+				checkedP2P = true
+				to := msg.GetTo()[0]
+				other := parties[(to.Index+1)%n]
+				if other.PartyID().Index == msg.GetFrom().Index {
+					other = parties[(to.Index+2)%n]
+				}
+
+				// a point-to-point message cannot be redirected to another party, whether or not the recipients are changed
+				ok, tErr := other.UpdateFromBytesWithRouting(bz, routing)
+				assert.False(t, ok)
+				assert.True(t, errors.Is(tErr, tss.ErrInvalidSignature))
+				redirected := *routing
+				redirected.To = []*tss.PartyID{other.PartyID()}
+				ok, tErr = other.UpdateFromBytesWithRouting(bz, &redirected)
+				assert.False(t, ok)
+				assert.True(t, errors.Is(tErr, tss.ErrInvalidSignature))
+			}
+			test.RouteMessage(parties, msg, errCh)
+		case <-endCh:
+			ended++
+		}
+	}
+	assert.True(t, checkedBroadcast)
+	assert.True(t, checkedP2P)
+}
+
+func TestECDSAIdentitySignedMessages(t *testing.T) {
+	keys, pIDs, err := ecdsakeygen.LoadKeygenTestFixturesRandomSet(test.TestThreshold+1, test.TestParticipants)
+	if err != nil {
+		t.Skip("no ecdsa keygen fixtures:", err)
+	}
+	session := test.NewSession(tss.S256(), pIDs, test.TestThreshold, nil)
+	assert.NoError(t, session.SetIdentityKeys())
+	n := len(pIDs)
+	outCh := make(chan tss.Message, n*n)
+	endCh := make(chan *common.SignatureData, n)
+	errCh := make(chan *tss.Error, n*n)
+	parties := make([]tss.Party, n)
+	for i, params := range session.Params {
+		parties[i] = signing.NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh)
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the signing completes over signed messages, while a message with a bad signature is rejected
+	tampered := false
+	for ended := 0; ended < n; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			bz, routing, err := msg.WireBytes()
+			assert.NoError(t, err)
+			if !tampered && msg.IsBroadcast() {
+				tampered = true
+				forged := *routing
+				forged.Signature = append([]byte{}, routing.Signature...)
+				forged.Signature[0] ^= 1
+				ok, tErr := parties[(msg.GetFrom().Index+1)%n].UpdateFromBytesWithRouting(bz, &forged)
+				assert.False(t, ok)
+				assert.True(t, errors.Is(tErr, tss.ErrInvalidSignature))
+			}
+			test.RouteMessage(parties, msg, errCh)
+		case <-endCh:
+			ended++
+		}
+	}
+	assert.True(t, tampered)
+}
+
+// failingSigner is an identity key whose every signature fails
+type failingSigner struct{}
+
+func (failingSigner) Public() crypto.PublicKey { return nil }
+
+func (failingSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, errors.New("the signing device is unavailable")
+}
+
+func TestPartyAbortsWhenSigningFails(t *testing.T) {
+	session := newKeygenSession(nil)
+	assert.NoError(t, session.SetIdentityKeys())
+	session.Params[0].SetSigner(failingSigner{})
+
+	outCh := make(chan tss.Message, len(session.PartyIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, 1)
+	P := keygen.NewLocalParty(session.Params[0], outCh, endCh)
+
+	// no unsigned message is sent
+	err := P.Start()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "the signing device is unavailable")
+	}
+	assert.Empty(t, outCh)
+}
@@ -0,0 +1,30 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// Logger receives the log lines of a party. The global common.Logger satisfies it, and is used when no Logger is set
+// on the Parameters; a per-session Logger can tag every line with e.g. the session id or party id.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warningf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+var _ Logger = common.Logger
+
+// roundLogger returns the Logger of a party's current round, or the global logger when the party has no round
+func roundLogger(rnd Round) Logger {
+	if rnd != nil {
+		return rnd.Params().Logger()
+	}
+	return common.Logger
+}
@@ -0,0 +1,58 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// prefixLogger tags the lines of one session and keeps them
+type prefixLogger struct {
+	prefix string
+	mtx    sync.Mutex
+	lines  []string
+}
+
+func (l *prefixLogger) logf(format string, args ...interface{}) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.lines = append(l.lines, l.prefix+fmt.Sprintf(format, args...))
+}
+
+func (l *prefixLogger) Debugf(format string, args ...interface{})   { l.logf(format, args...) }
+func (l *prefixLogger) Infof(format string, args ...interface{})    { l.logf(format, args...) }
+func (l *prefixLogger) Warningf(format string, args ...interface{}) { l.logf(format, args...) }
+func (l *prefixLogger) Errorf(format string, args ...interface{})   { l.logf(format, args...) }
+
+func TestSessionLoggerReceivesPartyLogs(t *testing.T) {
+	logger := &prefixLogger{prefix: "[session-a] "}
+	session := newKeygenSession(func(_ int, params *tss.Parameters) {
+		params.SetLogger(logger)
+	})
+	pIDs := session.PartyIDs
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	P := keygen.NewLocalParty(session.Params[1], outCh, endCh)
+	if err := P.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)))
+
+	logger.mtx.Lock()
+	defer logger.mtx.Unlock()
+	assert.Contains(t, logger.lines, fmt.Sprintf("[session-a] party %s: %s round 1 starting", pIDs[1], keygen.TaskName))
+	assert.Contains(t, logger.lines, fmt.Sprintf("[session-a] party %s round 1 update: %s", pIDs[1], keygen.NewKGRound1Message(pIDs[0], big.NewInt(1))))
+}
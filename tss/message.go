@@ -7,7 +7,9 @@
 package tss
 
 import (
+	"crypto/elliptic"
 	"fmt"
+	"sync/atomic"
 
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -49,6 +51,12 @@ type (
 		ValidateBasic() bool
 	}
 
+	// CurveValidator is implemented by the message contents that hold scalars or point coordinates. Their ValidateBasic
+	// bounds these by the largest registered curve, and ValidateCurve by the curve that the session runs on.
+	CurveValidator interface {
+		ValidateCurve(ec elliptic.Curve) bool
+	}
+
 	// MessageRouting holds the full routing information for the message, consumed by the transport
 	MessageRouting struct {
 		// which participant this message came from
@@ -61,6 +69,12 @@ type (
 		IsToOldCommittee bool
 		// whether the message should be sent to both old and new committee participants
 		IsToOldAndNewCommittees bool
+		// the session this message belongs to; must be delivered alongside the wire bytes when set
+		SessionID []byte
+		// the protocol version the message was encoded with; must be delivered alongside the wire bytes
+		Version uint32
+		// the identity-key signature of the sender; must be delivered alongside the wire bytes when set
+		Signature []byte
 	}
 
 	// Implements ParsedMessage; this is a concrete implementation of what messages produced by a LocalParty look like
@@ -68,6 +82,8 @@ type (
 		MessageRouting
 		content MessageContent
 		wire    *MessageWrapper
+		// the parameters that last verified the signature, so that it is verified once per party
+		verifiedBy atomic.Value
 	}
 )
 
@@ -94,12 +110,35 @@ func NewMessageWrapper(routing MessageRouting, content MessageContent) *MessageW
 		IsBroadcast:             routing.IsBroadcast,
 		IsToOldCommittee:        routing.IsToOldCommittee,
 		IsToOldAndNewCommittees: routing.IsToOldAndNewCommittees,
+		SessionId:               routing.SessionID,
+		Version:                 routing.Version,
+		Signature:               routing.Signature,
 		From:                    routing.From.MessageWrapper_PartyID,
 		To:                      to,
 		Message:                 any,
 	}
 }
 
+// StampMessage labels a message produced by a round with the session id and protocol version of these parameters,
+// then signs it when an identity key was set. Rounds pass every outgoing message through it before handing it to the
+// transport, and abort when it fails rather than send a message that the recipients would reject.
+func (params *Parameters) StampMessage(msg ParsedMessage) error {
+	mm, ok := msg.(*MessageImpl)
+	if !ok {
+		return nil
+	}
+	if 0 < len(params.sessionID) {
+		mm.SessionID = params.sessionID
+		mm.wire.SessionId = params.sessionID
+	}
+	mm.Version = params.Version()
+	mm.wire.Version = mm.Version
+	if err := params.SignMessage(mm); err != nil {
+		return fmt.Errorf("failed to sign %s: %w", mm.Type(), err)
+	}
+	return nil
+}
+
 // ----- //
 
 func NewMessage(meta MessageRouting, content MessageContent, wire *MessageWrapper) ParsedMessage {
@@ -0,0 +1,133 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"time"
+)
+
+type (
+	// Observer receives the progress of the parties whose Parameters it was set on.
+	// The methods are called synchronously, some of them with the party's lock held and some concurrently from the
+	// goroutines of a round; they should return quickly and must not call back into the party.
+	Observer interface {
+		RoundStarted(event RoundEvent)
+		// RoundFinished is called once a round has received all of its messages, before the next round starts
+		RoundFinished(event RoundEvent)
+		// MessageStored is called when a message is first stored by the party; retransmissions are not reported again
+		MessageStored(event MessageEvent)
+		// ProofChecked is called after a proof sent by another party was verified, whether or not it passed
+		ProofChecked(event ProofEvent)
+		// PartyBlamed is called when the party fails with an error that names culprits
+		PartyBlamed(err *Error)
+	}
+
+	RoundEvent struct {
+		Task  string
+		Party *PartyID
+		Round int
+		// Duration is the time spent in the round; it is zero for RoundStarted
+		Duration time.Duration
+	}
+
+	MessageEvent struct {
+		Task        string
+		Party       *PartyID
+		Round       int
+		From        *PartyID
+		Type        string
+		IsBroadcast bool
+	}
+
+	ProofEvent struct {
+		Task   string
+		Party  *PartyID
+		Round  int
+		Prover *PartyID
+		Proof  ProofType
+		OK     bool
+	}
+
+	ProofType string
+
+	// NopObserver ignores every event; embed it to implement only some of the Observer methods
+	NopObserver struct{}
+)
+
+const (
+	ProofDLN      ProofType = "dln"
+	ProofMod      ProofType = "mod"
+	ProofFac      ProofType = "fac"
+	ProofPaillier ProofType = "paillier"
+	ProofVSS      ProofType = "vss-share"
+	ProofSchnorr  ProofType = "schnorr"
+	ProofSchnorrV ProofType = "schnorr-v"
+	ProofMtARange ProofType = "mta-range"
+	ProofMtABob   ProofType = "mta-bob"
+	ProofMtABobWC ProofType = "mta-bob-wc"
+)
+
+var _ Observer = NopObserver{}
+
+func (NopObserver) RoundStarted(RoundEvent)    {}
+func (NopObserver) RoundFinished(RoundEvent)   {}
+func (NopObserver) MessageStored(MessageEvent) {}
+func (NopObserver) ProofChecked(ProofEvent)    {}
+func (NopObserver) PartyBlamed(*Error)         {}
+
+// ObserveProof reports the outcome of verifying a proof from `prover` to the observer of `params`, if there is one
+func ObserveProof(params *Parameters, task string, round int, prover *PartyID, proof ProofType, ok bool) {
+	if o := params.Observer(); o != nil {
+		o.ProofChecked(ProofEvent{Task: task, Party: params.PartyID(), Round: round, Prover: prover, Proof: proof, OK: ok})
+	}
+}
+
+// ----- //
+// the following must be called with the party's lock held
+
+func observeRoundStarted(p Party, task string) {
+	rnd := p.round()
+	p.lifecycle().roundStart = time.Now()
+	if o := rnd.Params().Observer(); o != nil {
+		o.RoundStarted(RoundEvent{Task: task, Party: rnd.Params().PartyID(), Round: rnd.RoundNumber()})
+	}
+}
+
+func observeRoundFinished(p Party, task string) {
+	rnd := p.round()
+	if o := rnd.Params().Observer(); o != nil {
+		o.RoundFinished(RoundEvent{
+			Task:     task,
+			Party:    rnd.Params().PartyID(),
+			Round:    rnd.RoundNumber(),
+			Duration: time.Since(p.lifecycle().roundStart),
+		})
+	}
+}
+
+func observeMessageStored(rnd Round, task string, msg ParsedMessage) {
+	if o := rnd.Params().Observer(); o != nil {
+		o.MessageStored(MessageEvent{
+			Task:        task,
+			Party:       rnd.Params().PartyID(),
+			Round:       rnd.RoundNumber(),
+			From:        msg.GetFrom(),
+			Type:        msg.Type(),
+			IsBroadcast: msg.IsBroadcast(),
+		})
+	}
+}
+
+func observeBlame(p Party, err *Error) {
+	rnd := p.round()
+	if err == nil || len(err.Culprits()) == 0 || rnd == nil {
+		return
+	}
+	if o := rnd.Params().Observer(); o != nil {
+		o.PartyBlamed(err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// recordingObserver counts the events that it receives
+type recordingObserver struct {
+	tss.NopObserver
+	mtx                                  sync.Mutex
+	started, finished, stored, badProofs int
+	proofs                               map[tss.ProofType]int
+	blamed                               []*tss.Error
+}
+
+func (o *recordingObserver) RoundStarted(tss.RoundEvent) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.started++
+}
+
+func (o *recordingObserver) RoundFinished(tss.RoundEvent) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.finished++
+}
+
+func (o *recordingObserver) MessageStored(tss.MessageEvent) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.stored++
+}
+
+func (o *recordingObserver) ProofChecked(event tss.ProofEvent) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.proofs[event.Proof]++
+	if !event.OK {
+		o.badProofs++
+	}
+}
+
+func (o *recordingObserver) PartyBlamed(err *tss.Error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.blamed = append(o.blamed, err)
+}
+
+func TestObserverReceivesEvents(t *testing.T) {
+	observer := &recordingObserver{proofs: make(map[tss.ProofType]int)}
+	session := newKeygenSession(func(_ int, params *tss.Parameters) {
+		params.SetObserver(observer)
+	})
+	pIDs := session.PartyIDs
+	n := len(pIDs)
+	outCh := make(chan tss.Message, n*n*3)
+	endCh := make(chan *keygen.LocalPartySaveData, n)
+	runKeygen(t, newKeygenParties(session, outCh, endCh), outCh, endCh)
+
+	// three rounds; a broadcast in round 1 and a broadcast and a p2p message in round 2
+	assert.Equal(t, 3*n, observer.started)
+	assert.Equal(t, 3*n, observer.finished)
+	assert.Equal(t, 3*n*(n-1), observer.stored)
+	assert.Equal(t, n*(n-1), observer.proofs[tss.ProofSchnorr])
+	assert.Equal(t, n*(n-1), observer.proofs[tss.ProofVSS])
+	assert.Zero(t, observer.badProofs)
+	assert.Empty(t, observer.blamed)
+
+	// a party that fails with culprits is reported as blamed
+	P := keygen.NewLocalParty(session.Params[1], outCh, endCh)
+	if err := P.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)))
+	_, err := P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(2)))
+	if assert.Len(t, observer.blamed, 1) {
+		assert.Equal(t, err, observer.blamed[0])
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.14.0
+// source: protob/options.proto
+
+package tss
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+//
+// How a protocol message is delivered, see tss.ClassifyMessage
+type Routing int32
+
+const (
+	Routing_ROUTING_UNSPECIFIED Routing = 0
+	Routing_BROADCAST           Routing = 1 // to every party of its committee
+	Routing_P2P                 Routing = 2 // to a single party
+	Routing_BROADCAST_OR_P2P    Routing = 3 // either way, as told by the MessageRouting of the message
+)
+
+// Enum value maps for Routing.
+var (
+	Routing_name = map[int32]string{
+		0: "ROUTING_UNSPECIFIED",
+		1: "BROADCAST",
+		2: "P2P",
+		3: "BROADCAST_OR_P2P",
+	}
+	Routing_value = map[string]int32{
+		"ROUTING_UNSPECIFIED": 0,
+		"BROADCAST":           1,
+		"P2P":                 2,
+		"BROADCAST_OR_P2P":    3,
+	}
+)
+
+func (x Routing) Enum() *Routing {
+	p := new(Routing)
+	*p = x
+	return p
+}
+
+func (x Routing) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Routing) Descriptor() protoreflect.EnumDescriptor {
+	return file_protob_options_proto_enumTypes[0].Descriptor()
+}
+
+func (Routing) Type() protoreflect.EnumType {
+	return &file_protob_options_proto_enumTypes[0]
+}
+
+func (x Routing) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Routing.Descriptor instead.
+func (Routing) EnumDescriptor() ([]byte, []int) {
+	return file_protob_options_proto_rawDescGZIP(), []int{0}
+}
+
+//
+// The parties that a protocol message is delivered to; only resharing has more than one committee
+type Committee int32
+
+const (
+	Committee_ALL_PARTIES            Committee = 0
+	Committee_NEW_COMMITTEE          Committee = 1
+	Committee_OLD_COMMITTEE          Committee = 2
+	Committee_OLD_AND_NEW_COMMITTEES Committee = 3
+)
+
+// Enum value maps for Committee.
+var (
+	Committee_name = map[int32]string{
+		0: "ALL_PARTIES",
+		1: "NEW_COMMITTEE",
+		2: "OLD_COMMITTEE",
+		3: "OLD_AND_NEW_COMMITTEES",
+	}
+	Committee_value = map[string]int32{
+		"ALL_PARTIES":            0,
+		"NEW_COMMITTEE":          1,
+		"OLD_COMMITTEE":          2,
+		"OLD_AND_NEW_COMMITTEES": 3,
+	}
+)
+
+func (x Committee) Enum() *Committee {
+	p := new(Committee)
+	*p = x
+	return p
+}
+
+func (x Committee) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Committee) Descriptor() protoreflect.EnumDescriptor {
+	return file_protob_options_proto_enumTypes[1].Descriptor()
+}
+
+func (Committee) Type() protoreflect.EnumType {
+	return &file_protob_options_proto_enumTypes[1]
+}
+
+func (x Committee) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Committee.Descriptor instead.
+func (Committee) EnumDescriptor() ([]byte, []int) {
+	return file_protob_options_proto_rawDescGZIP(), []int{1}
+}
+
+var file_protob_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*uint32)(nil),
+		Field:         50001,
+		Name:          "binance.tsslib.round",
+		Tag:           "varint,50001,opt,name=round",
+		Filename:      "protob/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*Routing)(nil),
+		Field:         50002,
+		Name:          "binance.tsslib.routing",
+		Tag:           "varint,50002,opt,name=routing,enum=binance.tsslib.Routing",
+		Filename:      "protob/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*Committee)(nil),
+		Field:         50003,
+		Name:          "binance.tsslib.committee",
+		Tag:           "varint,50003,opt,name=committee,enum=binance.tsslib.Committee",
+		Filename:      "protob/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// optional uint32 round = 50001;
+	E_Round = &file_protob_options_proto_extTypes[0] // the round that the message is sent in; 0 when that varies or is before round 1
+	// optional binance.tsslib.Routing routing = 50002;
+	E_Routing = &file_protob_options_proto_extTypes[1]
+	// optional binance.tsslib.Committee committee = 50003;
+	E_Committee = &file_protob_options_proto_extTypes[2]
+)
+
+var File_protob_options_proto protoreflect.FileDescriptor
+
+var file_protob_options_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x62, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e,
+	0x74, 0x73, 0x73, 0x6c, 0x69, 0x62, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0x50, 0x0a, 0x07, 0x52, 0x6f, 0x75, 0x74,
+	0x69, 0x6e, 0x67, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x4f, 0x55, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09,
+	0x42, 0x52, 0x4f, 0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x50,
+	0x32, 0x50, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43, 0x41, 0x53,
+	0x54, 0x5f, 0x4f, 0x52, 0x5f, 0x50, 0x32, 0x50, 0x10, 0x03, 0x2a, 0x5e, 0x0a, 0x09, 0x43, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x4c, 0x4c, 0x5f, 0x50,
+	0x41, 0x52, 0x54, 0x49, 0x45, 0x53, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x45, 0x57, 0x5f,
+	0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x45, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4f,
+	0x4c, 0x44, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x45, 0x10, 0x02, 0x12, 0x1a,
+	0x0a, 0x16, 0x4f, 0x4c, 0x44, 0x5f, 0x41, 0x4e, 0x44, 0x5f, 0x4e, 0x45, 0x57, 0x5f, 0x43, 0x4f,
+	0x4d, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x45, 0x53, 0x10, 0x03, 0x3a, 0x37, 0x0a, 0x05, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd1, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x72, 0x6f,
+	0x75, 0x6e, 0x64, 0x3a, 0x54, 0x0a, 0x07, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1f,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xd2, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x62, 0x69, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x2e, 0x74, 0x73, 0x73, 0x6c, 0x69, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x52, 0x07, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x3a, 0x5a, 0x0a, 0x09, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x74, 0x65, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xd3, 0x86, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x19, 0x2e, 0x62, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x74, 0x73, 0x73, 0x6c, 0x69, 0x62,
+	0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x65, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x65, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x6e, 0x62, 0x2d, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2f, 0x74, 0x73,
+	0x73, 0x2d, 0x6c, 0x69, 0x62, 0x2f, 0x76, 0x32, 0x2f, 0x74, 0x73, 0x73, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protob_options_proto_rawDescOnce sync.Once
+	file_protob_options_proto_rawDescData = file_protob_options_proto_rawDesc
+)
+
+func file_protob_options_proto_rawDescGZIP() []byte {
+	file_protob_options_proto_rawDescOnce.Do(func() {
+		file_protob_options_proto_rawDescData = protoimpl.X.CompressGZIP(file_protob_options_proto_rawDescData)
+	})
+	return file_protob_options_proto_rawDescData
+}
+
+var file_protob_options_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_protob_options_proto_goTypes = []interface{}{
+	(Routing)(0),                        // 0: binance.tsslib.Routing
+	(Committee)(0),                      // 1: binance.tsslib.Committee
+	(*descriptorpb.MessageOptions)(nil), // 2: google.protobuf.MessageOptions
+}
+var file_protob_options_proto_depIdxs = []int32{
+	2, // 0: binance.tsslib.round:extendee -> google.protobuf.MessageOptions
+	2, // 1: binance.tsslib.routing:extendee -> google.protobuf.MessageOptions
+	2, // 2: binance.tsslib.committee:extendee -> google.protobuf.MessageOptions
+	0, // 3: binance.tsslib.routing:type_name -> binance.tsslib.Routing
+	1, // 4: binance.tsslib.committee:type_name -> binance.tsslib.Committee
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	3, // [3:5] is the sub-list for extension type_name
+	0, // [0:3] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_protob_options_proto_init() }
+func file_protob_options_proto_init() {
+	if File_protob_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_protob_options_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   0,
+			NumExtensions: 3,
+			NumServices:   0,
+		},
+		GoTypes:           file_protob_options_proto_goTypes,
+		DependencyIndexes: file_protob_options_proto_depIdxs,
+		EnumInfos:         file_protob_options_proto_enumTypes,
+		ExtensionInfos:    file_protob_options_proto_extTypes,
+	}.Build()
+	File_protob_options_proto = out.File
+	file_protob_options_proto_rawDesc = nil
+	file_protob_options_proto_goTypes = nil
+	file_protob_options_proto_depIdxs = nil
+}
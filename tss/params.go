@@ -7,11 +7,16 @@
 package tss
 
 import (
+	"crypto"
 	"crypto/elliptic"
 	"crypto/rand"
+	"fmt"
 	"io"
+	"math/big"
 	"runtime"
 	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
 )
 
 type (
@@ -23,13 +28,22 @@ type (
 		threshold           int
 		concurrency         int
 		safePrimeGenTimeout time.Duration
+		roundTimeout        time.Duration
 		// proof session info
-		nonce int
+		nonce     int
+		sessionID []byte
+		version   uint32
 		// for keygen
 		noProofMod bool
 		noProofFac bool
 		// random sources
 		partialKeyRand, rand io.Reader
+		observer             Observer
+		logger               Logger
+		// identity keys, see SetSigner and SetPeerKeys
+		signer        crypto.Signer
+		peerKeys      *PeerKeys
+		encryptShares bool
 	}
 
 	ReSharingParameters struct {
@@ -87,6 +101,11 @@ func (params *Parameters) SafePrimeGenTimeout() time.Duration {
 	return params.safePrimeGenTimeout
 }
 
+// RoundTimeout is the maximum time a party started with StartWithContext waits in a single round; zero means no limit.
+func (params *Parameters) RoundTimeout() time.Duration {
+	return params.roundTimeout
+}
+
 // The concurrency level must be >= 1.
 func (params *Parameters) SetConcurrency(concurrency int) {
 	params.concurrency = concurrency
@@ -96,6 +115,103 @@ func (params *Parameters) SetSafePrimeGenTimeout(timeout time.Duration) {
 	params.safePrimeGenTimeout = timeout
 }
 
+func (params *Parameters) SetRoundTimeout(timeout time.Duration) {
+	params.roundTimeout = timeout
+}
+
+// SessionID identifies the protocol run that these parameters belong to; it is nil unless set with SetSessionID
+func (params *Parameters) SessionID() []byte {
+	return params.sessionID
+}
+
+// SetSessionID sets an id that all parties of one protocol run agree on beforehand.
+// It is carried in every message and mixed into the SSID, so messages of concurrent sessions cannot be mixed up.
+func (params *Parameters) SetSessionID(sessionID []byte) {
+	params.sessionID = append([]byte(nil), sessionID...)
+}
+
+// SSIDNonce is the nonce mixed into the SSID of the proofs; it is zero when no session id was set
+func (params *Parameters) SSIDNonce() *big.Int {
+	if len(params.sessionID) == 0 {
+		return big.NewInt(0)
+	}
+	return new(big.Int).SetBytes(common.SHA512_256(params.sessionID))
+}
+
+// Version is the wire protocol version that the party speaks; it is ProtocolVersion unless set with SetVersion
+func (params *Parameters) Version() uint32 {
+	if params.version == 0 {
+		return ProtocolVersion
+	}
+	return params.version
+}
+
+// SetVersion sets the protocol version agreed on by the parties, usually with a VersionHandshake.
+// Messages of any other version are rejected by the party.
+func (params *Parameters) SetVersion(version uint32) error {
+	if !IsSupportedVersion(version) {
+		return fmt.Errorf("%w: version %d, supported are %d to %d", ErrIncompatibleVersion, version, MinProtocolVersion, ProtocolVersion)
+	}
+	params.version = version
+	return nil
+}
+
+// Logger is the logger set with SetLogger, or the global common.Logger when none was set
+func (params *Parameters) Logger() Logger {
+	if params.logger == nil {
+		return common.Logger
+	}
+	return params.logger
+}
+
+// SetLogger sets the logger used by the party and its rounds, e.g. to tag the log lines of one session
+func (params *Parameters) SetLogger(logger Logger) {
+	params.logger = logger
+}
+
+// Signer is the identity key that signs the outgoing messages; it is nil unless set with SetSigner
+func (params *Parameters) Signer() crypto.Signer {
+	return params.signer
+}
+
+// SetSigner sets the identity key of this party, an ed25519.PrivateKey or *ecdsa.PrivateKey or a remote signer for one.
+// Every outgoing message is signed with it, and the other parties must register its public key in their PeerKeys.
+func (params *Parameters) SetSigner(signer crypto.Signer) {
+	params.signer = signer
+}
+
+// PeerKeys holds the identity keys of the other parties; it is nil unless set with SetPeerKeys
+func (params *Parameters) PeerKeys() *PeerKeys {
+	return params.peerKeys
+}
+
+// SetPeerKeys makes the party reject every message that is not signed by the identity key registered for its sender.
+// For resharing the keys of both committees must be registered.
+func (params *Parameters) SetPeerKeys(peerKeys *PeerKeys) {
+	params.peerKeys = peerKeys
+}
+
+// EncryptShares is true when the secret shares sent point-to-point are encrypted to the identity key of their recipient
+func (params *Parameters) EncryptShares() bool {
+	return params.encryptShares
+}
+
+// SetEncryptShares makes the party encrypt the VSS shares it sends to the identity key of each recipient, and reject
+// shares that were not encrypted, so that they can pass through a relay. It needs SetSigner with an ed25519.PrivateKey
+// or *ecdsa.PrivateKey, and SetPeerKeys; every party of the session must set it.
+func (params *Parameters) SetEncryptShares() {
+	params.encryptShares = true
+}
+
+// Observer receives the progress events of the party; it is nil unless set with SetObserver
+func (params *Parameters) Observer() Observer {
+	return params.observer
+}
+
+func (params *Parameters) SetObserver(observer Observer) {
+	params.observer = observer
+}
+
 func (params *Parameters) NoProofMod() bool {
 	return params.noProofMod
 }
@@ -188,3 +304,11 @@ func (rgParams *ReSharingParameters) IsNewCommittee() bool {
 	}
 	return false
 }
+
+// ValidateCurve checks the scalars and point coordinates of a received message against the curve of the session
+func (params *Parameters) ValidateCurve(msg ParsedMessage) error {
+	if content, ok := msg.Content().(CurveValidator); ok && !content.ValidateCurve(params.EC()) {
+		return invalidMessage(fmt.Errorf("message holds integers too large for the curve: %s", msg))
+	}
+	return nil
+}
@@ -7,18 +7,33 @@
 package tss
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
+	"google.golang.org/protobuf/proto"
 )
 
 type Party interface {
 	Start() *Error
-	// The main entry point when updating a party's state from the wire.
+	// StartWithContext starts the party like Start, but aborts it when ctx is done or when a round
+	// takes longer than the round timeout configured on the Parameters
+	StartWithContext(ctx context.Context) *Error
+	// Aborted delivers the error that aborted the party, with the parties it was still waiting for as culprits unless
+	// its context was canceled
+	Aborted() <-chan *Error
+	// Updates a party's state from the wire without a session id, version or signature.
 	// isBroadcast should represent whether the message was received via a reliable broadcast
+	//
+	// Deprecated: use UpdateFromBytesWithRouting; a party with a session id or identity keys rejects every message
+	// given to UpdateFromBytes.
 	UpdateFromBytes(wireBytes []byte, from *PartyID, isBroadcast bool) (ok bool, err *Error)
+	// The main entry point when updating a party's state from the wire, with the MessageRouting that the
+	// transport delivered along with the wire bytes
+	UpdateFromBytesWithRouting(wireBytes []byte, routing *MessageRouting) (ok bool, err *Error)
 	// You may use this entry point to update a party's state when running locally or in tests
 	Update(msg ParsedMessage) (ok bool, err *Error)
 	Running() bool
@@ -36,12 +51,25 @@ type Party interface {
 	advance()
 	lock()
 	unlock()
+	lifecycle() *lifecycle
 }
 
 type BaseParty struct {
 	mtx        sync.Mutex
 	rnd        Round
 	FirstRound Round
+	lc         *lifecycle
+}
+
+// lifecycle tracks the state used to abort a party that was started with a context
+type lifecycle struct {
+	advanced chan struct{} // signalled when the party moves to the next round
+	finished chan struct{} // closed when the party has no more rounds
+	abortCh  chan *Error
+	abortErr *Error
+
+	task       string    // set when the party is started or resumed
+	roundStart time.Time // when the current round started, for the Observer
 }
 
 func (p *BaseParty) Running() bool {
@@ -57,6 +85,12 @@ func (p *BaseParty) WaitingFor() []*PartyID {
 	return p.rnd.WaitingFor()
 }
 
+func (p *BaseParty) Aborted() <-chan *Error {
+	p.lock()
+	defer p.unlock()
+	return p.lifecycle().abortCh
+}
+
 func (p *BaseParty) WrapError(err error, culprits ...*PartyID) *Error {
 	if p.rnd == nil {
 		return NewError(err, "", -1, nil, culprits...)
@@ -67,13 +101,34 @@ func (p *BaseParty) WrapError(err error, culprits ...*PartyID) *Error {
 // an implementation of ValidateMessage that is shared across the different types of parties (keygen, signing, dynamic groups)
 func (p *BaseParty) ValidateMessage(msg ParsedMessage) (bool, *Error) {
 	if msg == nil || msg.Content() == nil {
-		return false, p.WrapError(fmt.Errorf("received nil msg: %s", msg))
+		return false, p.WrapError(invalidMessage(fmt.Errorf("received nil msg: %s", msg)))
 	}
 	if msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg))
+		return false, p.WrapError(invalidMessage(fmt.Errorf("received msg with an invalid sender: %s", msg)))
 	}
 	if !msg.ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom())
+		return false, p.WrapError(invalidMessage(fmt.Errorf("message failed ValidateBasic: %s", msg)), msg.GetFrom())
+	}
+	return true, nil
+}
+
+// an implementation of storing a message that is shared across the different types of parties; `store` holds one message per sender.
+// a byte-identical retransmission of a stored message is ignored, while a different message from the same sender is equivocation.
+func (p *BaseParty) StoreMessageOnce(store []ParsedMessage, msg ParsedMessage) (bool, *Error) {
+	fromPIdx := msg.GetFrom().Index
+	if fromPIdx < 0 || len(store) <= fromPIdx {
+		return false, p.WrapError(invalidMessage(fmt.Errorf("received msg with an out of range sender index: %s", msg)), msg.GetFrom())
+	}
+	if stored := store[fromPIdx]; stored != nil {
+		if proto.Equal(stored.WireMsg().GetMessage(), msg.WireMsg().GetMessage()) {
+			roundLogger(p.rnd).Debugf("ignored a retransmitted message: %s", msg)
+			return true, nil
+		}
+		return false, p.WrapError(fmt.Errorf("%w: received two different %s from %s", ErrEquivocation, msg.Type(), msg.GetFrom()), msg.GetFrom())
+	}
+	store[fromPIdx] = msg
+	if p.rnd != nil {
+		observeMessageStored(p.rnd, p.lifecycle().task, msg)
 	}
 	return true, nil
 }
@@ -103,6 +158,15 @@ func (p *BaseParty) round() Round {
 
 func (p *BaseParty) advance() {
 	p.rnd = p.rnd.NextRound()
+	lc := p.lifecycle()
+	if p.rnd == nil {
+		close(lc.finished)
+		return
+	}
+	select {
+	case lc.advanced <- struct{}{}:
+	default:
+	}
 }
 
 func (p *BaseParty) lock() {
@@ -113,9 +177,41 @@ func (p *BaseParty) unlock() {
 	p.mtx.Unlock()
 }
 
+// must be called with the mutex held
+func (p *BaseParty) lifecycle() *lifecycle {
+	if p.lc == nil {
+		p.lc = &lifecycle{
+			advanced: make(chan struct{}, 1),
+			finished: make(chan struct{}),
+			abortCh:  make(chan *Error, 1),
+		}
+	}
+	return p.lc
+}
+
 // ----- //
 
 func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
+	return BaseStartWithContext(context.Background(), p, task, prepare...)
+}
+
+// BaseStartWithContext is an implementation of StartWithContext that is shared across the different types of parties.
+// When ctx can be cancelled or the Parameters carry a round timeout, a watcher aborts the party once either fires.
+func BaseStartWithContext(ctx context.Context, p Party, task string, prepare ...func(Round) *Error) *Error {
+	if err := baseStart(p, task, prepare...); err != nil {
+		return err
+	}
+	p.lock()
+	timeout := p.round().Params().RoundTimeout()
+	lc := p.lifecycle()
+	p.unlock()
+	if ctx.Done() != nil || 0 < timeout {
+		go watchRounds(ctx, p, task, lc, timeout)
+	}
+	return nil
+}
+
+func baseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 	p.lock()
 	defer p.unlock()
 	if p.PartyID() == nil || !p.PartyID().ValidateBasic() {
@@ -128,6 +224,7 @@ func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 	if err := p.setRound(round); err != nil {
 		return err
 	}
+	p.lifecycle().task = task
 	if 1 < len(prepare) {
 		return p.WrapError(errors.New("too many prepare functions given to Start(); 1 allowed"))
 	}
@@ -136,47 +233,75 @@ func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 			return err
 		}
 	}
-	common.Logger.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
+	log := round.Params().Logger()
+	log.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
 	defer func() {
-		common.Logger.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
+		log.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
 	}()
-	return p.round().Start()
+	if err := p.round().Start(); err != nil {
+		observeBlame(p, err)
+		return err
+	}
+	observeRoundStarted(p, task)
+	return nil
 }
 
 // an implementation of Update that is shared across the different types of parties (keygen, signing, dynamic groups)
 func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 	// fast-fail on an invalid message; do not lock the mutex yet
 	if _, err := p.ValidateMessage(msg); err != nil {
+		p.lock()
+		observeBlame(p, err)
+		p.unlock()
 		return false, err
 	}
 	// lock the mutex. need this mtx unlock hook; L108 is recursive so cannot use defer
 	r := func(ok bool, err *Error) (bool, *Error) {
+		observeBlame(p, err)
 		p.unlock()
 		return ok, err
 	}
 	p.lock() // data is written to P state below
-	common.Logger.Debugf("party %s received message: %s", p.PartyID(), msg.String())
+	log := roundLogger(p.round())
+	if err := p.lifecycle().abortErr; err != nil {
+		return r(false, err)
+	}
+	if rnd := p.round(); rnd != nil && !bytes.Equal(msg.WireMsg().GetSessionId(), rnd.Params().SessionID()) {
+		if len(msg.WireMsg().GetSessionId()) == 0 {
+			// most likely given to UpdateFromBytes, which cannot carry the session id
+			return r(false, p.WrapError(invalidMessage(errors.New("received a message without a session id, deliver it with UpdateFromBytesWithRouting")), msg.GetFrom()))
+		}
+		return r(false, p.WrapError(invalidMessage(errors.New("received a message that belongs to another session")), msg.GetFrom()))
+	}
+	if rnd := p.round(); rnd != nil {
+		if err := rnd.Params().CheckVersion(msg); err != nil {
+			return r(false, p.WrapError(err, msg.GetFrom()))
+		}
+	}
+	log.Debugf("party %s received message: %s", p.PartyID(), msg.String())
 	if p.round() != nil {
-		common.Logger.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
+		log.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
 	}
 	if ok, err := p.StoreMessage(msg); err != nil || !ok {
 		return r(false, err)
 	}
 	if p.round() != nil {
-		common.Logger.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
+		log.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
 		if _, err := p.round().Update(); err != nil {
 			return r(false, err)
 		}
 		if p.round().CanProceed() {
+			observeRoundFinished(p, task)
 			if p.advance(); p.round() != nil {
 				if err := p.round().Start(); err != nil {
 					return r(false, err)
 				}
+				observeRoundStarted(p, task)
 				rndNum := p.round().RoundNumber()
-				common.Logger.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
+				log.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
 			} else {
 				// finished! the round implementation will have sent the data through the `end` channel.
-				common.Logger.Infof("party %s: %s finished!", p.PartyID(), task)
+				log.Infof("party %s: %s finished!", p.PartyID(), task)
 			}
 			p.unlock()                      // recursive so can't defer after return
 			return BaseUpdate(p, msg, task) // re-run round update or finish)
@@ -185,3 +310,54 @@ func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 	}
 	return r(true, nil)
 }
+
+// watchRounds aborts the party when ctx is done or when the current round does not finish within `timeout`
+func watchRounds(ctx context.Context, p Party, task string, lc *lifecycle, timeout time.Duration) {
+	var timer *time.Timer
+	var expired <-chan time.Time
+	if 0 < timeout {
+		timer = time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		select {
+		case <-lc.finished:
+			return
+		case <-lc.advanced:
+			if timer != nil {
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(timeout)
+			}
+		case <-ctx.Done():
+			baseAbort(p, task, ctx.Err())
+			return
+		case <-expired:
+			baseAbort(p, task, ErrRoundTimeout)
+			return
+		}
+	}
+}
+
+// baseAbort stops a running party and reports the parties that it was still waiting for as culprits; a canceled
+// context is no fault of theirs, so it blames no one
+func baseAbort(p Party, task string, cause error) {
+	p.lock()
+	defer p.unlock()
+	rnd, lc := p.round(), p.lifecycle()
+	if rnd == nil || lc.abortErr != nil {
+		return
+	}
+	culprits := make([]*PartyID, 0, len(rnd.WaitingFor()))
+	for _, Pj := range rnd.WaitingFor() {
+		if Pj.KeyInt().Cmp(p.PartyID().KeyInt()) != 0 && !errors.Is(cause, context.Canceled) {
+			culprits = append(culprits, Pj)
+		}
+	}
+	lc.abortErr = rnd.WrapError(cause, culprits...)
+	observeBlame(p, lc.abortErr)
+	rnd.Params().Logger().Warningf("party %s: %s round %d aborted: %v", p.PartyID(), task, rnd.RoundNumber(), cause)
+	lc.abortCh <- lc.abortErr
+}
@@ -0,0 +1,290 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// newKeygenSession creates the parameters of the eddsa keygen parties of a new test session
+func newKeygenSession(configure func(i int, params *tss.Parameters)) *test.Session {
+	return test.NewSession(tss.Edwards(), tss.GenerateTestPartyIDs(test.TestParticipants), test.TestThreshold, configure)
+}
+
+// newKeygenParties creates an eddsa keygen party for every party of `session`
+func newKeygenParties(session *test.Session, outCh chan tss.Message, endCh chan *keygen.LocalPartySaveData) []tss.Party {
+	parties := make([]tss.Party, len(session.Params))
+	for i, params := range session.Params {
+		parties[i] = keygen.NewLocalParty(params, outCh, endCh)
+	}
+	return parties
+}
+
+// runKeygen starts `parties` and routes their messages until every party has output its save data
+func runKeygen(t *testing.T, parties []tss.Party, outCh chan tss.Message, endCh chan *keygen.LocalPartySaveData) []*keygen.LocalPartySaveData {
+	errCh := make(chan *tss.Error, len(parties))
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saves := make([]*keygen.LocalPartySaveData, 0, len(parties))
+	for len(saves) < len(parties) {
+		select {
+		case err := <-errCh:
+			t.Fatal(err)
+		case msg := <-outCh:
+			test.RouteMessage(parties, msg, errCh)
+		case save := <-endCh:
+			saves = append(saves, save)
+		}
+	}
+	return saves
+}
+
+func TestRoundTimeoutAbortsParty(t *testing.T) {
+	session := newKeygenSession(nil)
+	session.Params[0].SetRoundTimeout(200 * time.Millisecond)
+	n := len(session.PartyIDs)
+
+	outCh := make(chan tss.Message, n)
+	endCh := make(chan *keygen.LocalPartySaveData, 1)
+	P := keygen.NewLocalParty(session.Params[0], outCh, endCh)
+	if err := P.StartWithContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// no messages are ever delivered, so round 1 should time out waiting for every peer
+	select {
+	case err := <-P.Aborted():
+		assert.True(t, errors.Is(err, tss.ErrRoundTimeout), "abort cause should be the round timeout")
+		assert.Equal(t, 1, err.Round())
+		assert.Len(t, err.Culprits(), n-1, "all peers should be blamed")
+		assert.Equal(t, tss.KindTimeout, err.Kind())
+	case <-time.After(5 * time.Second):
+		t.Fatal("party was not aborted after the round timeout")
+	}
+
+	// the aborted party no longer accepts messages
+	ok, err := P.Update((<-outCh).(tss.ParsedMessage))
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+}
+
+func TestCancelledContextAbortsParty(t *testing.T) {
+	session := newKeygenSession(nil)
+
+	outCh := make(chan tss.Message, len(session.PartyIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, 1)
+	P := keygen.NewLocalParty(session.Params[0], outCh, endCh)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := P.StartWithContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	select {
+	case err := <-P.Aborted():
+		assert.True(t, errors.Is(err, context.Canceled), "abort cause should be the context error")
+		assert.Equal(t, tss.KindCanceled, err.Kind())
+		assert.Empty(t, err.Culprits(), "a canceled party blames no one")
+	case <-time.After(5 * time.Second):
+		t.Fatal("party was not aborted after the context was cancelled")
+	}
+}
+
+func TestExpiredContextIsATimeout(t *testing.T) {
+	session := newKeygenSession(nil)
+	n := len(session.PartyIDs)
+
+	outCh := make(chan tss.Message, n)
+	endCh := make(chan *keygen.LocalPartySaveData, 1)
+	P := keygen.NewLocalParty(session.Params[0], outCh, endCh)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := P.StartWithContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the peers were only slow, so they are blamed for a timeout rather than for an invalid message
+	select {
+	case err := <-P.Aborted():
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, tss.KindTimeout, err.Kind())
+		assert.False(t, errors.Is(err, tss.ErrInvalidMessage))
+		assert.Len(t, err.Culprits(), n-1)
+		assert.Equal(t, tss.KindTimeout, err.BlameReport(nil).Kind)
+	case <-time.After(5 * time.Second):
+		t.Fatal("party was not aborted when the context expired")
+	}
+}
+
+func TestErrorKindOfContextErrors(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	deadline := tss.NewError(fmt.Errorf("session: %w", context.DeadlineExceeded), "keygen", 1, pIDs[0], pIDs[1])
+	assert.Equal(t, tss.KindTimeout, deadline.Kind())
+	assert.False(t, errors.Is(deadline, tss.ErrInvalidMessage))
+	assert.False(t, errors.Is(deadline, tss.ErrInternal))
+
+	canceled := tss.NewError(context.Canceled, "keygen", 1, pIDs[0])
+	assert.Equal(t, tss.KindCanceled, canceled.Kind())
+	assert.False(t, errors.Is(canceled, tss.ErrInvalidMessage))
+	assert.False(t, errors.Is(canceled, tss.ErrInternal))
+
+	// an untyped cause with culprits is still an invalid message of theirs
+	assert.Equal(t, tss.KindInvalidMessage, tss.NewError(errors.New("bad share"), "keygen", 2, pIDs[0], pIDs[1]).Kind())
+}
+
+// firstMessageFrom starts `parties` and returns the first message that party `from` outputs
+func firstMessageFrom(t *testing.T, parties []tss.Party, outCh <-chan tss.Message, from int) tss.Message {
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for msg := range outCh {
+		if msg.GetFrom().Index == from {
+			return msg
+		}
+	}
+	return nil
+}
+
+func TestMessageFromAnotherSessionIsRejected(t *testing.T) {
+	session := newKeygenSession(func(i int, params *tss.Parameters) {
+		params.SetSessionID([]byte(fmt.Sprintf("session-%d", i%2)))
+	})
+	assert.NotEqual(t, session.Params[0].SSIDNonce(), session.Params[1].SSIDNonce())
+	n := len(session.PartyIDs)
+	outCh := make(chan tss.Message, n*n)
+	endCh := make(chan *keygen.LocalPartySaveData, n)
+	P0 := keygen.NewLocalParty(session.Params[0], outCh, endCh)
+	P1 := keygen.NewLocalParty(session.Params[1], outCh, endCh)
+	P2 := keygen.NewLocalParty(session.Params[2], outCh, endCh)
+
+	msg := firstMessageFrom(t, []tss.Party{P0, P1, P2}, outCh, 0)
+	bz, routing, err := msg.WireBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("session-0"), routing.SessionID)
+
+	ok, tErr := P1.UpdateFromBytesWithRouting(bz, routing)
+	assert.False(t, ok)
+	if assert.NotNil(t, tErr) {
+		assert.Equal(t, []*tss.PartyID{session.PartyIDs[0]}, tErr.Culprits())
+	}
+
+	// the party of the same session accepts the message with its routing
+	ok, tErr = P2.UpdateFromBytesWithRouting(bz, routing)
+	assert.True(t, ok)
+	assert.Nil(t, tErr)
+
+	// but not without it, as UpdateFromBytes cannot carry the session id
+	ok, tErr = P2.UpdateFromBytes(bz, msg.GetFrom(), msg.IsBroadcast())
+	assert.False(t, ok)
+	if assert.NotNil(t, tErr) {
+		assert.Contains(t, tErr.Error(), "UpdateFromBytesWithRouting")
+	}
+}
+
+func TestECDSAMessageFromAnotherSessionIsRejected(t *testing.T) {
+	keys, pIDs, err := ecdsakeygen.LoadKeygenTestFixturesRandomSet(test.TestThreshold+1, test.TestParticipants)
+	if err != nil {
+		t.Skip("no ecdsa keygen fixtures:", err)
+	}
+	session := test.NewSession(tss.S256(), pIDs, test.TestThreshold, func(i int, params *tss.Parameters) {
+		params.SetSessionID([]byte(fmt.Sprintf("session-%d", i%2)))
+	})
+	n := len(pIDs)
+	outCh := make(chan tss.Message, n*n)
+	endCh := make(chan *common.SignatureData, n)
+	parties := make([]tss.Party, n)
+	for i, params := range session.Params {
+		parties[i] = signing.NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh)
+	}
+
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// every message of round 1 from party 0 is accepted by party 2 only
+	accepted, rejected := 0, 0
+	for len(outCh) > 0 {
+		msg := <-outCh
+		if msg.GetFrom().Index != 0 {
+			continue
+		}
+		bz, routing, err := msg.WireBytes()
+		assert.NoError(t, err)
+		for _, P := range parties[1:] {
+			if to := msg.GetTo(); to != nil && to[0].Index != P.PartyID().Index {
+				continue
+			}
+			ok, tErr := P.UpdateFromBytesWithRouting(bz, routing)
+			if P.PartyID().Index == 2 {
+				assert.True(t, ok)
+				assert.Nil(t, tErr)
+				accepted++
+			} else {
+				assert.False(t, ok, "a party of another session")
+				assert.NotNil(t, tErr)
+				rejected++
+			}
+		}
+	}
+	assert.Equal(t, 2, accepted)
+	assert.Equal(t, 2, rejected)
+}
+
+func TestEquivocationIsBlamed(t *testing.T) {
+	session := newKeygenSession(nil)
+	pIDs := session.PartyIDs
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	P := keygen.NewLocalParty(session.Params[1], outCh, endCh)
+	if err := P.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	// a byte-identical retransmission is tolerated
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	// a different message for the same round is equivocation by the sender
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(2)))
+	assert.False(t, ok)
+	if assert.NotNil(t, err) {
+		assert.True(t, errors.Is(err, tss.ErrEquivocation))
+		assert.Equal(t, tss.KindEquivocation, err.Kind())
+		assert.Equal(t, []*tss.PartyID{pIDs[0]}, err.Culprits())
+	}
+
+	// the first message was kept, so it is still taken as a retransmission
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)))
+	assert.True(t, ok, "the first message should be kept")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,63 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// seededRand expands a seed into a stream of random bytes with AES-256 in counter mode
+type seededRand struct {
+	mtx    sync.Mutex
+	stream cipher.Stream
+}
+
+// NewSeededRand returns a deterministic random source for SetRand and SetPartialKeyRand that expands `seed`.
+// Anyone who knows the seed can compute every secret drawn from it, so it must be kept as secret as the key shares
+// and never be used for two sessions.
+func NewSeededRand(seed []byte) io.Reader {
+	block, err := aes.NewCipher(common.SHA512_256([]byte("tss-lib rand"), seed))
+	if err != nil {
+		panic(err) // the key is always 32 bytes long
+	}
+	return &seededRand{stream: cipher.NewCTR(block, make([]byte, aes.BlockSize))}
+}
+
+func (r *seededRand) Read(p []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for i := range p {
+		p[i] = 0
+	}
+	r.stream.XORKeyStream(p, p)
+	return len(p), nil
+}
+
+// ForkRand returns `n` random sources for the goroutines of a round, one for each, seeded from Rand in turn.
+// The goroutines then draw the same values from a deterministic Rand whatever order they run in, which Replay relies on.
+// When Rand is the default crypto/rand source, it is returned for every goroutine.
+func (params *Parameters) ForkRand(n int) ([]io.Reader, error) {
+	forks := make([]io.Reader, n)
+	for i := range forks {
+		if params.rand == rand.Reader {
+			forks[i] = params.rand
+			continue
+		}
+		seed := make([]byte, 32)
+		if _, err := io.ReadFull(params.rand, seed); err != nil {
+			return nil, err
+		}
+		forks[i] = NewSeededRand(seed)
+	}
+	return forks, nil
+}
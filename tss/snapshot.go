@@ -0,0 +1,138 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// SnapshotMessage is a stored message in the snapshot of a party.
+// It keeps the whole MessageWrapper so that the sender and session id are restored along with the content.
+type SnapshotMessage struct {
+	FromIndex int
+	Wire      []byte
+}
+
+// SnapshotMessages encodes a message store of a party; the empty slots are kept as nil
+func SnapshotMessages(store []ParsedMessage) ([]*SnapshotMessage, error) {
+	snapshot := make([]*SnapshotMessage, len(store))
+	for j, msg := range store {
+		if msg == nil {
+			continue
+		}
+		wire, err := proto.Marshal(msg.WireMsg())
+		if err != nil {
+			return nil, err
+		}
+		snapshot[j] = &SnapshotMessage{FromIndex: msg.GetFrom().Index, Wire: wire}
+	}
+	return snapshot, nil
+}
+
+// RestoreMessages decodes a message store that was encoded with SnapshotMessages into `store`, which must be as long as the snapshot
+func RestoreMessages(store []ParsedMessage, snapshot []*SnapshotMessage) error {
+	if len(store) != len(snapshot) {
+		return fmt.Errorf("snapshot has %d message slots, expected %d", len(snapshot), len(store))
+	}
+	for j, sm := range snapshot {
+		if sm == nil {
+			store[j] = nil
+			continue
+		}
+		wire := new(MessageWrapper)
+		if err := proto.Unmarshal(sm.Wire, wire); err != nil {
+			return err
+		}
+		if wire.GetFrom() == nil || wire.GetMessage() == nil {
+			return errors.New("snapshot has a message without a sender or content")
+		}
+		if sm.FromIndex != j {
+			return fmt.Errorf("snapshot has a message from party %d in slot %d", sm.FromIndex, j)
+		}
+		from := NewPartyID(wire.From.GetId(), wire.From.GetMoniker(), new(big.Int).SetBytes(wire.From.GetKey()))
+		from.Index = sm.FromIndex
+		msg, err := parseWrappedMessage(wire, from, nil)
+		if err != nil {
+			return err
+		}
+		store[j] = msg
+	}
+	return nil
+}
+
+// ----- //
+
+// BaseSnapshot runs `snapshot` on the current round of a running party while holding its lock, so that it sees a consistent state
+func BaseSnapshot(p Party, snapshot func(round Round) ([]byte, error)) ([]byte, error) {
+	p.lock()
+	defer p.unlock()
+	if p.round() == nil {
+		return nil, errors.New("could not snapshot. this party is not running")
+	}
+	return snapshot(p.round())
+}
+
+func BaseResume(p Party, round Round, task string) *Error {
+	return BaseResumeWithContext(context.Background(), p, round, task)
+}
+
+// BaseResumeWithContext puts a party that was rebuilt from a snapshot back into `round`, which must be marked as started,
+// then runs it forward with the messages that it had already stored. The round is not started again, so nothing is re-sent.
+func BaseResumeWithContext(ctx context.Context, p Party, round Round, task string) *Error {
+	if err := baseResume(p, round, task); err != nil {
+		return err
+	}
+	p.lock()
+	lc := p.lifecycle()
+	running := p.round() != nil
+	p.unlock()
+	if running {
+		if timeout := round.Params().RoundTimeout(); ctx.Done() != nil || 0 < timeout {
+			go watchRounds(ctx, p, task, lc, timeout)
+		}
+	}
+	return nil
+}
+
+func baseResume(p Party, round Round, task string) (err *Error) {
+	p.lock()
+	defer p.unlock()
+	defer func() { observeBlame(p, err) }()
+	if p.PartyID() == nil || !p.PartyID().ValidateBasic() {
+		return p.WrapError(fmt.Errorf("could not resume. this party has an invalid PartyID: %+v", p.PartyID()))
+	}
+	if err := p.setRound(round); err != nil {
+		return err
+	}
+	p.lifecycle().task = task
+	log := round.Params().Logger()
+	log.Infof("party %s: %s round %d resumed", round.Params().PartyID(), task, round.RoundNumber())
+	observeRoundStarted(p, task)
+	for {
+		if _, err := p.round().Update(); err != nil {
+			return err
+		}
+		if !p.round().CanProceed() {
+			return nil
+		}
+		observeRoundFinished(p, task)
+		if p.advance(); p.round() == nil {
+			log.Infof("party %s: %s finished!", p.PartyID(), task)
+			return nil
+		}
+		if err := p.round().Start(); err != nil {
+			return err
+		}
+		observeRoundStarted(p, task)
+		log.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, p.round().RoundNumber())
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+type (
+	// Transcript is the record of the messages that a party sent and received in one session, in the order they were seen.
+	// The point-to-point messages it received are only as confidential as their content; see Parameters.SetEncryptShares.
+	// Unless the random source was recorded with Recorder.RecordRand, it holds no secrets of the party itself;
+	// RandSeed reveals every secret that the party drew in the session, so a transcript with it must be kept as secret
+	// as the key share of the party.
+	Transcript struct {
+		PartyID    string               `json:"party_id"`
+		PartyKey   []byte               `json:"party_key"`
+		PartyIndex int                  `json:"party_index"`
+		SessionID  []byte               `json:"session_id,omitempty"`
+		Version    uint32               `json:"version"`
+		RandSeed   []byte               `json:"rand_seed,omitempty"`
+		Messages   []*TranscriptMessage `json:"messages"`
+	}
+
+	// TranscriptMessage is a message in a Transcript.
+	// Wire is the whole MessageWrapper, so that the routing metadata is kept along with the content.
+	TranscriptMessage struct {
+		Outbound  bool   `json:"outbound"`
+		FromIndex int    `json:"from_index"`
+		Wire      []byte `json:"wire"`
+	}
+
+	// Recorder captures the messages of a party into a Transcript.
+	// Pass it every message the party outputs and every message it is updated with; it is safe for concurrent use.
+	Recorder struct {
+		mtx        sync.Mutex
+		transcript Transcript
+	}
+)
+
+// NewRecorder creates a recorder for the party of `params`
+func NewRecorder(params *Parameters) *Recorder {
+	return &Recorder{
+		transcript: Transcript{
+			PartyID:    params.PartyID().GetId(),
+			PartyKey:   params.PartyID().GetKey(),
+			PartyIndex: params.PartyID().Index,
+			SessionID:  params.SessionID(),
+			Version:    params.Version(),
+			Messages:   make([]*TranscriptMessage, 0),
+		},
+	}
+}
+
+// RecordRand replaces the random sources of `params` with ones seeded from a new seed, drawn from its Rand, and records
+// the seed so that Replay gives the replayed party the same randomness. Call it before the party is created.
+// The seed reveals every secret that the party draws in the session; see Transcript.
+func (r *Recorder) RecordRand(params *Parameters) error {
+	seed := make([]byte, 32)
+	if _, err := io.ReadFull(params.Rand(), seed); err != nil {
+		return err
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.transcript.RandSeed = seed
+	r.transcript.SetRand(params)
+	return nil
+}
+
+// RecordOutbound records a message that the party sent to its out channel
+func (r *Recorder) RecordOutbound(msg Message) error {
+	return r.record(msg, true)
+}
+
+// RecordInbound records a message that was received for the party, as parsed from the wire
+func (r *Recorder) RecordInbound(msg Message) error {
+	return r.record(msg, false)
+}
+
+// Update records `msg` as received and then updates `p` with it
+func (r *Recorder) Update(p Party, msg ParsedMessage) (bool, *Error) {
+	if err := r.RecordInbound(msg); err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+// Transcript returns a copy of the messages recorded so far
+func (r *Recorder) Transcript() *Transcript {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	transcript := r.transcript
+	transcript.Messages = append([]*TranscriptMessage{}, r.transcript.Messages...)
+	return &transcript
+}
+
+// WriteFile writes the transcript recorded so far to a file at `path` as JSON
+func (r *Recorder) WriteFile(path string) error {
+	bz, err := json.Marshal(r.Transcript())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bz, 0600)
+}
+
+func (r *Recorder) record(msg Message, outbound bool) error {
+	if msg == nil || msg.GetFrom() == nil || msg.WireMsg() == nil {
+		return errors.New("cannot record a message without a sender or content")
+	}
+	wire, err := proto.Marshal(msg.WireMsg())
+	if err != nil {
+		return err
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.transcript.Messages = append(r.transcript.Messages, &TranscriptMessage{
+		Outbound:  outbound,
+		FromIndex: msg.GetFrom().Index,
+		Wire:      wire,
+	})
+	return nil
+}
+
+// ----- //
+
+// ReadTranscript reads a transcript written by Recorder.WriteFile
+func ReadTranscript(path string) (*Transcript, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	transcript := new(Transcript)
+	if err = json.Unmarshal(bz, transcript); err != nil {
+		return nil, err
+	}
+	return transcript, nil
+}
+
+// SetRand gives `params` the random sources recorded with Recorder.RecordRand; it does nothing when none was recorded
+func (t *Transcript) SetRand(params *Parameters) {
+	if len(t.RandSeed) == 0 {
+		return
+	}
+	params.SetRand(NewSeededRand(common.SHA512_256([]byte("rand"), t.RandSeed)))
+	params.SetPartialKeyRand(NewSeededRand(common.SHA512_256([]byte("partial key rand"), t.RandSeed)))
+}
+
+// Inbound returns the messages that the party received, parsed and in the order they were recorded
+func (t *Transcript) Inbound() ([]ParsedMessage, error) {
+	msgs := make([]ParsedMessage, 0, len(t.Messages))
+	for i, tm := range t.Messages {
+		if tm.Outbound {
+			continue
+		}
+		wire := new(MessageWrapper)
+		if err := proto.Unmarshal(tm.Wire, wire); err != nil {
+			return nil, fmt.Errorf("transcript message %d: %w", i, err)
+		}
+		if wire.GetFrom() == nil || wire.GetMessage() == nil {
+			return nil, fmt.Errorf("transcript message %d has no sender or content", i)
+		}
+		from := NewPartyID(wire.From.GetId(), wire.From.GetMoniker(), new(big.Int).SetBytes(wire.From.GetKey()))
+		from.Index = tm.FromIndex
+		msg, err := parseWrappedMessage(wire, from, nil)
+		if err != nil {
+			return nil, fmt.Errorf("transcript message %d: %w", i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// Replay starts `p` and updates it with the received messages of `transcript` in their recorded order, returning the
+// first error of the party. `p` must be a fresh party of the recorded one, created with the same Parameters and input.
+// When the random source was recorded with Recorder.RecordRand, Replay gives it to the parameters of `p`, and the party
+// computes the same values and sends the same messages as in the recorded session; otherwise it draws new randomness,
+// and the replay only reproduces the checks that do not depend on it. Its out channel must be buffered or drained.
+func Replay(p Party, transcript *Transcript) *Error {
+	if !bytes.Equal(p.PartyID().GetKey(), transcript.PartyKey) || p.PartyID().Index != transcript.PartyIndex {
+		return p.WrapError(fmt.Errorf("the transcript was recorded by party %s, not %s", transcript.PartyID, p.PartyID()))
+	}
+	transcript.SetRand(p.FirstRound().Params())
+	msgs, err := transcript.Inbound()
+	if err != nil {
+		return p.WrapError(err)
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if _, err := p.Update(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
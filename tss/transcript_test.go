@@ -0,0 +1,192 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// routeRecording routes `msg` like test.RouteMessage, recording the messages that party 0 sends and receives
+func routeRecording(t *testing.T, parties []tss.Party, recorder *tss.Recorder, msg tss.Message, errCh chan<- *tss.Error) {
+	if msg.GetFrom().Index == 0 {
+		assert.NoError(t, recorder.RecordOutbound(msg))
+	}
+	for _, P := range parties {
+		if !addressedTo(msg, P.PartyID()) {
+			continue
+		}
+		if P.PartyID().Index != 0 {
+			test.SharedPartyUpdater(P, msg, errCh)
+			continue
+		}
+		bz, routing, err := msg.WireBytes()
+		assert.NoError(t, err)
+		parsed, err := tss.ParseWireMessageWithRouting(bz, routing)
+		assert.NoError(t, err)
+		if _, err := recorder.Update(P, parsed); err != nil {
+			errCh <- err
+		}
+	}
+}
+
+func addressedTo(msg tss.Message, pID *tss.PartyID) bool {
+	if msg.GetFrom().Index == pID.Index {
+		return false
+	}
+	for _, Pj := range msg.GetTo() {
+		if Pj.Index == pID.Index {
+			return true
+		}
+	}
+	return msg.GetTo() == nil
+}
+
+// writeAndReadTranscript passes the transcript of `recorder` through a file
+func writeAndReadTranscript(t *testing.T, recorder *tss.Recorder) *tss.Transcript {
+	path := filepath.Join(t.TempDir(), "transcript.json")
+	assert.NoError(t, recorder.WriteFile(path))
+	transcript, err := tss.ReadTranscript(path)
+	assert.NoError(t, err)
+	return transcript
+}
+
+// assertSameOutbound checks that the replay sent the messages that were recorded as sent, in any order
+func assertSameOutbound(t *testing.T, transcript *tss.Transcript, replayOut chan tss.Message) {
+	recorded := make([][]byte, 0, len(transcript.Messages))
+	for _, tm := range transcript.Messages {
+		if tm.Outbound {
+			recorded = append(recorded, tm.Wire)
+		}
+	}
+	replayed := make([][]byte, 0, len(replayOut))
+	for len(replayOut) > 0 {
+		bz, err := proto.Marshal((<-replayOut).WireMsg())
+		assert.NoError(t, err)
+		replayed = append(replayed, bz)
+	}
+	for _, wires := range [][][]byte{recorded, replayed} {
+		sort.Slice(wires, func(a, b int) bool { return bytes.Compare(wires[a], wires[b]) < 0 })
+	}
+	assert.Equal(t, recorded, replayed)
+}
+
+func TestTranscriptReplay(t *testing.T) {
+	session := newKeygenSession(nil)
+	pIDs := session.PartyIDs
+	n := len(pIDs)
+	recorder := tss.NewRecorder(session.Params[0])
+	assert.NoError(t, recorder.RecordRand(session.Params[0]))
+
+	outCh := make(chan tss.Message, n*n*3)
+	endCh := make(chan *keygen.LocalPartySaveData, n)
+	errCh := make(chan *tss.Error, n*n*3)
+	parties := newKeygenParties(session, outCh, endCh)
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var recorded *keygen.LocalPartySaveData
+	for ended := 0; ended < n; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			routeRecording(t, parties, recorder, msg, errCh)
+		case save := <-endCh:
+			if save.ShareID.Cmp(pIDs[0].KeyInt()) == 0 {
+				recorded = save
+			}
+			ended++
+		}
+	}
+
+	transcript := writeAndReadTranscript(t, recorder)
+	inbound, err := transcript.Inbound()
+	assert.NoError(t, err)
+	assert.Len(t, transcript.Messages, 3*(n-1)+n+1)
+	assert.Len(t, inbound, 3*(n-1))
+
+	replay := test.NewSession(tss.Edwards(), pIDs, test.TestThreshold, nil)
+	replayOut := make(chan tss.Message, n*3)
+	replayEnd := make(chan *keygen.LocalPartySaveData, 1)
+	P := keygen.NewLocalParty(replay.Params[0], replayOut, replayEnd)
+	if err := tss.Replay(P, transcript); err != nil {
+		t.Fatal(err)
+	}
+	replayed := <-replayEnd
+	assert.Equal(t, recorded.Xi, replayed.Xi)
+	assert.True(t, recorded.EDDSAPub.Equals(replayed.EDDSAPub))
+	assertSameOutbound(t, transcript, replayOut)
+
+	// a transcript is refused by the other parties
+	other := keygen.NewLocalParty(replay.Params[1], replayOut, replayEnd)
+	assert.NotNil(t, tss.Replay(other, transcript))
+}
+
+func TestECDSASigningTranscriptReplay(t *testing.T) {
+	keys, pIDs, err := ecdsakeygen.LoadKeygenTestFixturesRandomSet(test.TestThreshold+1, test.TestParticipants)
+	if err != nil {
+		t.Skip("no ecdsa keygen fixtures:", err)
+	}
+	session := test.NewSession(tss.S256(), pIDs, test.TestThreshold, nil)
+	recorder := tss.NewRecorder(session.Params[0])
+	assert.NoError(t, recorder.RecordRand(session.Params[0]))
+
+	// the rounds of ecdsa signing draw randomness in concurrent goroutines
+	n := len(pIDs)
+	msg := big.NewInt(42)
+	outCh := make(chan tss.Message, n*n)
+	endCh := make(chan *common.SignatureData, n)
+	errCh := make(chan *tss.Error, n*n)
+	parties := make([]tss.Party, n)
+	for i, params := range session.Params {
+		parties[i] = signing.NewLocalParty(msg, params, keys[i], outCh, endCh)
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var recorded *common.SignatureData
+	for ended := 0; ended < n; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			routeRecording(t, parties, recorder, msg, errCh)
+		case recorded = <-endCh:
+			ended++
+		}
+	}
+
+	transcript := writeAndReadTranscript(t, recorder)
+	replay := test.NewSession(tss.S256(), pIDs, test.TestThreshold, nil)
+	replayOut := make(chan tss.Message, n*n*3)
+	replayEnd := make(chan *common.SignatureData, 1)
+	P := signing.NewLocalParty(msg, replay.Params[0], keys[0], replayOut, replayEnd)
+	if err := tss.Replay(P, transcript); err != nil {
+		t.Fatal(err)
+	}
+	replayed := <-replayEnd
+	assert.Equal(t, recorded.GetSignature(), replayed.GetSignature())
+	assertSameOutbound(t, transcript, replayOut)
+}
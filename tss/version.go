@@ -0,0 +1,215 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// ProtocolVersion is the newest wire protocol version spoken by this library.
+	// It is bumped whenever the content of a message or the way it is verified changes incompatibly.
+	ProtocolVersion uint32 = 1
+	// MinProtocolVersion is the oldest wire protocol version that this library can still parse and run
+	MinProtocolVersion uint32 = 1
+)
+
+// ErrIncompatibleVersion is the cause of the error reported for a message encoded with a protocol version that is not
+// supported, or that differs from the version agreed on for the session
+var ErrIncompatibleVersion = errors.New("incompatible protocol version")
+
+// VersionAdapter converts the content of a message encoded with an older protocol version into its current form
+type VersionAdapter func(content MessageContent) (MessageContent, error)
+
+var (
+	versionAdaptersMtx sync.RWMutex
+	versionAdapters    = make(map[uint32]VersionAdapter)
+)
+
+// RegisterVersionAdapter sets the adapter that ParseWireMessage applies to the content of messages of `version`.
+// It is only needed for versions below ProtocolVersion whose messages must be rewritten before the rounds can read them.
+func RegisterVersionAdapter(version uint32, adapter VersionAdapter) {
+	versionAdaptersMtx.Lock()
+	defer versionAdaptersMtx.Unlock()
+	versionAdapters[version] = adapter
+}
+
+// IsSupportedVersion returns true if this library can run the protocol at `version`
+func IsSupportedVersion(version uint32) bool {
+	return MinProtocolVersion <= version && version <= ProtocolVersion
+}
+
+// NegotiateVersion returns the highest version that is in every one of the given [min, max] ranges and in ours
+func NegotiateVersion(hellos ...*VersionHello) (uint32, error) {
+	lo, hi := MinProtocolVersion, ProtocolVersion
+	for _, hello := range hellos {
+		if !hello.ValidateBasic() {
+			return 0, errors.New("NegotiateVersion: invalid version range")
+		}
+		if lo < hello.GetMinVersion() {
+			lo = hello.GetMinVersion()
+		}
+		if hello.GetMaxVersion() < hi {
+			hi = hello.GetMaxVersion()
+		}
+	}
+	if hi < lo {
+		return 0, fmt.Errorf("%w: the parties have no version in common", ErrIncompatibleVersion)
+	}
+	return hi, nil
+}
+
+// messageVersion is the protocol version of a received message; messages from releases before versioning carry none
+func messageVersion(wire *MessageWrapper) uint32 {
+	if v := wire.GetVersion(); v != 0 {
+		return v
+	}
+	return 1
+}
+
+// CheckVersion returns an error matching ErrIncompatibleVersion and ErrInvalidMessage if `msg` was not encoded with the
+// version of the session
+func (params *Parameters) CheckVersion(msg ParsedMessage) error {
+	if version := messageVersion(msg.WireMsg()); version != params.Version() {
+		return invalidMessage(fmt.Errorf("%w: received a message of version %d, the session runs version %d",
+			ErrIncompatibleVersion, version, params.Version()))
+	}
+	return nil
+}
+
+func adaptContent(version uint32, content MessageContent) (MessageContent, error) {
+	if !IsSupportedVersion(version) {
+		return nil, fmt.Errorf("%w: message has version %d, supported are %d to %d",
+			ErrIncompatibleVersion, version, MinProtocolVersion, ProtocolVersion)
+	}
+	versionAdaptersMtx.RLock()
+	adapter, ok := versionAdapters[version]
+	versionAdaptersMtx.RUnlock()
+	if !ok || version == ProtocolVersion {
+		return content, nil
+	}
+	return adapter(content)
+}
+
+// ----- //
+
+// VersionHandshake agrees on the protocol version of a session before round 1.
+// Each party broadcasts its Hello and passes the hellos of the others to Update; once every party was heard from,
+// the highest version spoken by all of them is set on the Parameters, which must then be used to create the party.
+type VersionHandshake struct {
+	params  *Parameters
+	parties []*PartyID
+	others  int // the number of parties other than this one, whose hellos are awaited
+
+	mtx     sync.Mutex
+	hellos  map[string]*VersionHello // keyed by the sender's key
+	version uint32
+}
+
+// NewVersionHandshake prepares the handshake between `parties`, which for resharing are both committees. This party
+// need not be one of `parties`.
+func NewVersionHandshake(params *Parameters, parties []*PartyID) *VersionHandshake {
+	others := 0
+	for _, Pj := range parties {
+		if Pj.KeyInt().Cmp(params.PartyID().KeyInt()) != 0 {
+			others++
+		}
+	}
+	return &VersionHandshake{
+		params:  params,
+		parties: parties,
+		others:  others,
+		hellos:  make(map[string]*VersionHello),
+	}
+}
+
+// Hello is the message of this party that must be broadcast to the others
+func (h *VersionHandshake) Hello() ParsedMessage {
+	meta := MessageRouting{
+		From:        h.params.PartyID(),
+		IsBroadcast: true,
+		SessionID:   h.params.SessionID(),
+	}
+	content := &VersionHello{MinVersion: MinProtocolVersion, MaxVersion: ProtocolVersion}
+	return NewMessage(meta, content, NewMessageWrapper(meta, content))
+}
+
+// Update stores the hello of another party and returns true once the version was agreed on
+func (h *VersionHandshake) Update(msg ParsedMessage) (bool, *Error) {
+	hello, ok := msg.Content().(*VersionHello)
+	if !ok || !hello.ValidateBasic() {
+		return false, h.wrapError(errors.New("received an invalid version hello"), msg.GetFrom())
+	}
+	if !h.isParty(msg.GetFrom()) {
+		return false, h.wrapError(errors.New("received a version hello from an unknown party"), msg.GetFrom())
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.version != 0 {
+		return true, nil
+	}
+	h.hellos[string(msg.GetFrom().GetKey())] = hello
+	if len(h.hellos) < h.others {
+		return false, nil
+	}
+	hellos := make([]*VersionHello, 0, len(h.hellos))
+	for _, hello := range h.hellos {
+		hellos = append(hellos, hello)
+	}
+	version, err := NegotiateVersion(hellos...)
+	if err != nil {
+		return false, h.wrapError(err, h.unsupporting()...)
+	}
+	if err := h.params.SetVersion(version); err != nil {
+		return false, h.wrapError(err)
+	}
+	h.version = version
+	return true, nil
+}
+
+// Version returns the agreed version, or false while the handshake is still running
+func (h *VersionHandshake) Version() (uint32, bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.version, h.version != 0
+}
+
+func (h *VersionHandshake) isParty(id *PartyID) bool {
+	if id == nil || id.KeyInt().Cmp(h.params.PartyID().KeyInt()) == 0 {
+		return false
+	}
+	for _, Pj := range h.parties {
+		if Pj.KeyInt().Cmp(id.KeyInt()) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupporting lists the parties that speak none of the versions supported by this party
+func (h *VersionHandshake) unsupporting() []*PartyID {
+	culprits := make([]*PartyID, 0)
+	for _, Pj := range h.parties {
+		hello, ok := h.hellos[string(Pj.GetKey())]
+		if ok && (hello.GetMaxVersion() < MinProtocolVersion || ProtocolVersion < hello.GetMinVersion()) {
+			culprits = append(culprits, Pj)
+		}
+	}
+	return culprits
+}
+
+func (h *VersionHandshake) wrapError(err error, culprits ...*PartyID) *Error {
+	return NewError(err, "version-handshake", 0, h.params.PartyID(), culprits...)
+}
+
+// ----- //
+
+func (m *VersionHello) ValidateBasic() bool {
+	return m != nil && 0 < m.GetMinVersion() && m.GetMinVersion() <= m.GetMaxVersion()
+}
@@ -0,0 +1,121 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestVersionHandshakeAndIncompatibleVersion(t *testing.T) {
+	session := newKeygenSession(nil)
+	pIDs, params := session.PartyIDs, session.Params
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	handshakes := make([]*tss.VersionHandshake, len(pIDs))
+	for i := range pIDs {
+		handshakes[i] = tss.NewVersionHandshake(params[i], pIDs)
+	}
+	for i, h := range handshakes {
+		bz, routing, err := h.Hello().WireBytes()
+		assert.NoError(t, err)
+		for j := range handshakes {
+			if j == i {
+				continue
+			}
+			pMsg, err := tss.ParseWireMessageWithRouting(bz, routing)
+			assert.NoError(t, err)
+			_, tErr := handshakes[j].Update(pMsg)
+			assert.Nil(t, tErr)
+		}
+	}
+	for i, h := range handshakes {
+		version, ok := h.Version()
+		assert.True(t, ok)
+		assert.Equal(t, tss.ProtocolVersion, version)
+		assert.Equal(t, tss.ProtocolVersion, params[i].Version())
+	}
+
+	// a party that only speaks a newer version is blamed by the handshake
+	fresh := newKeygenSession(nil)
+	h := tss.NewVersionHandshake(fresh.Params[0], fresh.PartyIDs)
+	for j := 1; j < len(fresh.PartyIDs); j++ {
+		max := tss.ProtocolVersion
+		if j == 1 {
+			max = tss.ProtocolVersion + 1
+		}
+		hello := &tss.VersionHello{MinVersion: max, MaxVersion: max}
+		meta := tss.MessageRouting{From: fresh.PartyIDs[j], IsBroadcast: true}
+		_, tErr := h.Update(tss.NewMessage(meta, hello, tss.NewMessageWrapper(meta, hello)))
+		if j < len(fresh.PartyIDs)-1 {
+			assert.Nil(t, tErr)
+		} else if assert.NotNil(t, tErr) {
+			assert.True(t, errors.Is(tErr, tss.ErrIncompatibleVersion))
+			assert.Equal(t, []*tss.PartyID{fresh.PartyIDs[1]}, tErr.Culprits())
+		}
+	}
+
+	P0 := keygen.NewLocalParty(params[0], outCh, endCh)
+	P1 := keygen.NewLocalParty(params[1], outCh, endCh)
+	msg := firstMessageFrom(t, []tss.Party{P0, P1}, outCh, 0)
+	bz, routing, err := msg.WireBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, tss.ProtocolVersion, routing.Version)
+
+	// a message of an unsupported version is rejected when it is parsed
+	newer := *routing
+	newer.Version = tss.ProtocolVersion + 1
+	_, err = tss.ParseWireMessageWithRouting(bz, &newer)
+	assert.True(t, errors.Is(err, tss.ErrIncompatibleVersion))
+
+	// a message from a release before versioning is read as version 1
+	unversioned := *routing
+	unversioned.Version = 0
+	pMsg, err := tss.ParseWireMessageWithRouting(bz, &unversioned)
+	assert.NoError(t, err)
+	ok, tErr := P1.Update(pMsg)
+	assert.True(t, ok)
+	assert.Nil(t, tErr)
+}
+
+func TestVersionHandshakeOfResharing(t *testing.T) {
+	oldPIDs, newPIDs := tss.GenerateTestPartyIDs(3), tss.GenerateTestPartyIDs(3, 3)
+	newParams := func() *tss.Parameters {
+		return tss.NewReSharingParameters(tss.S256(), tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs),
+			newPIDs[0], len(oldPIDs), 1, len(newPIDs), 1).Parameters
+	}
+	hello := &tss.VersionHello{MinVersion: tss.MinProtocolVersion, MaxVersion: tss.ProtocolVersion}
+	update := func(h *tss.VersionHandshake, from *tss.PartyID) bool {
+		meta := tss.MessageRouting{From: from, IsBroadcast: true}
+		ok, tErr := h.Update(tss.NewMessage(meta, hello, tss.NewMessageWrapper(meta, hello)))
+		assert.Nil(t, tErr)
+		return ok
+	}
+
+	// a party of the new committee waits for the hellos of both committees but its own
+	both := append(append([]*tss.PartyID{}, oldPIDs...), newPIDs...)
+	h := tss.NewVersionHandshake(newParams(), both)
+	others := append(append([]*tss.PartyID{}, oldPIDs...), newPIDs[1:]...)
+	for j, Pj := range others {
+		assert.Equal(t, j == len(others)-1, update(h, Pj))
+	}
+
+	// and for the hellos of every party when it is not one of them
+	h = tss.NewVersionHandshake(newParams(), oldPIDs)
+	for j, Pj := range oldPIDs {
+		assert.Equal(t, j == len(oldPIDs)-1, update(h, Pj))
+	}
+	version, ok := h.Version()
+	assert.True(t, ok)
+	assert.Equal(t, tss.ProtocolVersion, version)
+}
@@ -8,33 +8,82 @@ package tss
 
 import (
 	"errors"
+
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // Used externally to update a LocalParty with a valid ParsedMessage
 func ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+	return ParseWireMessageInSession(wireBytes, from, isBroadcast, nil)
+}
+
+// Used externally to update a LocalParty with a valid ParsedMessage when the parties were given a session id.
+// `sessionID` is the MessageRouting.SessionID that the transport delivered along with the wire bytes.
+func ParseWireMessageInSession(wireBytes []byte, from *PartyID, isBroadcast bool, sessionID []byte) (ParsedMessage, error) {
+	return ParseWireMessageWithRouting(wireBytes, &MessageRouting{From: from, IsBroadcast: isBroadcast, SessionID: sessionID})
+}
+
+// Used externally to update a LocalParty with a valid ParsedMessage, taking the sender, session id, protocol version
+// and signature from the MessageRouting that the transport delivered along with the wire bytes.
+// A message of an unsupported version is rejected with ErrIncompatibleVersion; one of an older supported version is
+// passed through the adapter registered for that version, if any.
+func ParseWireMessageWithRouting(wireBytes []byte, routing *MessageRouting) (ParsedMessage, error) {
 	wire := new(MessageWrapper)
 	wire.Message = new(anypb.Any)
-	wire.From = from.MessageWrapper_PartyID
-	wire.IsBroadcast = isBroadcast
+	wire.From = routing.From.MessageWrapper_PartyID
+	wire.IsBroadcast = routing.IsBroadcast
+	wire.IsToOldCommittee = routing.IsToOldCommittee
+	wire.IsToOldAndNewCommittees = routing.IsToOldAndNewCommittees
+	if routing.To != nil {
+		wire.To = make([]*MessageWrapper_PartyID, len(routing.To))
+		for i, Pj := range routing.To {
+			wire.To[i] = Pj.MessageWrapper_PartyID
+		}
+	}
+	wire.SessionId = routing.SessionID
+	wire.Version = routing.Version
+	wire.Signature = routing.Signature
 	if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
 		return nil, err
 	}
-	return parseWrappedMessage(wire, from)
+	return parseWrappedMessage(wire, routing.From, routing.To)
 }
 
-func parseWrappedMessage(wire *MessageWrapper, from *PartyID) (ParsedMessage, error) {
+// UpdateFromBytesWithRouting parses `wireBytes` with all of the routing metadata that the transport delivered and
+// updates `p` with the message. The parties implement Party.UpdateFromBytesWithRouting with it.
+func UpdateFromBytesWithRouting(p Party, wireBytes []byte, routing *MessageRouting) (bool, *Error) {
+	msg, err := ParseWireMessageWithRouting(wireBytes, routing)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func parseWrappedMessage(wire *MessageWrapper, from *PartyID, to []*PartyID) (ParsedMessage, error) {
 	m, err := wire.Message.UnmarshalNew()
 	if err != nil {
 		return nil, err
 	}
 	meta := MessageRouting{
-		From:        from,
-		IsBroadcast: wire.IsBroadcast,
+		From:                    from,
+		To:                      to,
+		IsBroadcast:             wire.IsBroadcast,
+		IsToOldCommittee:        wire.IsToOldCommittee,
+		IsToOldAndNewCommittees: wire.IsToOldAndNewCommittees,
+		SessionID:               wire.SessionId,
+		Version:                 wire.Version,
+		Signature:               wire.Signature,
+	}
+	content, ok := m.(MessageContent)
+	if !ok {
+		return nil, errors.New("ParseWireMessage: the message contained unknown content")
 	}
-	if content, ok := m.(MessageContent); ok {
-		return NewMessage(meta, content, wire), nil
+	// the hello of a version handshake must be readable by every version
+	if _, ok := content.(*VersionHello); !ok {
+		if content, err = adaptContent(messageVersion(wire), content); err != nil {
+			return nil, err
+		}
 	}
-	return nil, errors.New("ParseWireMessage: the message contained unknown content")
+	return NewMessage(meta, content, wire), nil
 }